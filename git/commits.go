@@ -2,6 +2,7 @@ package git
 
 import (
 	"io"
+	"time"
 
 	"github.com/mvader/gitql/sql"
 	"gopkg.in/src-d/go-git.v4"
@@ -21,14 +22,16 @@ func (commitsRelation) Name() string {
 
 func (commitsRelation) Schema() sql.Schema {
 	return sql.Schema{
-		sql.Field{"hash", sql.String},
-		sql.Field{"author_name", sql.String},
-		sql.Field{"author_email", sql.String},
-		sql.Field{"author_time", sql.Timestamp},
-		sql.Field{"comitter_name", sql.String},
-		sql.Field{"comitter_email", sql.String},
-		sql.Field{"comitter_time", sql.Timestamp},
-		sql.Field{"message", sql.String},
+		sql.Field{Name: "hash", Type: sql.String, Source: commitsRelationName},
+		sql.Field{Name: "author_name", Type: sql.String, Source: commitsRelationName},
+		sql.Field{Name: "author_email", Type: sql.String, Source: commitsRelationName},
+		sql.Field{Name: "author_time", Type: sql.Timestamp, Source: commitsRelationName},
+		sql.Field{Name: "author_tz", Type: sql.String, Source: commitsRelationName},
+		sql.Field{Name: "comitter_name", Type: sql.String, Source: commitsRelationName},
+		sql.Field{Name: "comitter_email", Type: sql.String, Source: commitsRelationName},
+		sql.Field{Name: "comitter_time", Type: sql.Timestamp, Source: commitsRelationName},
+		sql.Field{Name: "comitter_tz", Type: sql.String, Source: commitsRelationName},
+		sql.Field{Name: "message", Type: sql.String, Source: commitsRelationName},
 	}
 }
 
@@ -45,6 +48,14 @@ func (commitsRelation) Children() []sql.Node {
 	return []sql.Node{}
 }
 
+func (r *commitsRelation) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	return f(r)
+}
+
+func (r *commitsRelation) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	return r, nil
+}
+
 type iter struct {
 	i *git.CommitIter
 }
@@ -66,9 +77,17 @@ func commitToRow(c *git.Commit) sql.Row {
 		c.Author.Name,
 		c.Author.Email,
 		c.Author.When.Unix(),
+		timeZone(c.Author.When),
 		c.Committer.Name,
 		c.Committer.Email,
 		c.Committer.When.Unix(),
+		timeZone(c.Committer.When),
 		c.Message,
 	)
 }
+
+// timeZone returns the UTC offset of the given time as it is recorded in
+// commits, such as "+0200".
+func timeZone(t time.Time) string {
+	return t.Format("-0700")
+}
@@ -1,16 +1,19 @@
 package git
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"github.com/mvader/gitql/sql"
 )
 
 func TestCommitsRelation(t *testing.T) {
 	assert := assert.New(t)
-	var db sql.Database = NewDatabase("https://github.com/smola/galimatias.git")
-	assert.NotNil(db)
+	dir := newFixtureRepository(t)
+	defer os.RemoveAll(dir)
+
+	db, err := NewLocalDatabase(dir)
+	assert.Nil(err)
 	relations := db.Relations()
 	rel, ok := relations[commitsRelationName]
 	assert.True(ok)
@@ -25,7 +28,8 @@ func TestCommitsRelation(t *testing.T) {
 	assert.NotNil(row)
 	fields := row.Fields()
 	assert.NotNil(fields)
-	assert.IsType("", fields[1])
-	assert.IsType("", fields[2])
+	assert.Equal("gitql", fields[1])
+	assert.Equal("gitql@example.com", fields[2])
 	assert.IsType(int64(0), fields[3])
+	assert.Regexp(`^[+-]\d{4}$`, fields[4])
 }
@@ -1,36 +1,182 @@
 package git
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
 	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
 	"github.com/mvader/gitql/sql"
 )
 
 const (
 	commitsRelationName = "commits"
+	refsRelationName    = "refs"
 )
 
+// Database is a database with the relations of a git repository. Its name is
+// the name of the repository, so that it can be used to qualify relations in
+// queries, as in "galimatias.commits".
 type Database struct {
-	url string
-	cr sql.PhysicalRelation
+	name string
+	cr   sql.PhysicalRelation
+	rr   sql.PhysicalRelation
 }
 
+// NewDatabase returns a database with the repository cloned in memory from
+// the given URL.
+//
+// Deprecated: errors cloning the repository are ignored, use
+// NewClonedDatabase instead.
 func NewDatabase(url string) sql.Database {
 	r := git.NewMemoryRepository()
 	r.Clone(&git.CloneOptions{
 		URL: url,
 	})
+	return newDatabase(url, r)
+}
+
+// CloneOptions are the options to clone a repository with NewClonedDatabase.
+type CloneOptions struct {
+	// ReferenceName is the reference to clone, either a full name such as
+	// "refs/tags/v1.0.0" or the name of a branch. The remote HEAD is cloned
+	// if it is empty.
+	ReferenceName string
+	// SingleBranch fetches only the history of ReferenceName.
+	SingleBranch bool
+	// Depth limits the history fetched to the given number of commits. There
+	// is no limit if it is 0.
+	Depth int
+	// Progress receives the progress messages sent by the server, if any.
+	Progress io.Writer
+	// Auth are the credentials used to connect to the server, which can be
+	// created with NewHTTPAuth or NewSSHAuth.
+	Auth transport.AuthMethod
+}
+
+// NewHTTPAuth returns the credentials to clone from an HTTP server with basic
+// authentication.
+func NewHTTPAuth(user, password string) transport.AuthMethod {
+	return http.NewBasicAuth(user, password)
+}
+
+// NewSSHAuth returns the credentials to clone from an SSH server with the
+// private key in the given PEM file, which is decrypted with the given
+// password if it is not empty.
+func NewSSHAuth(user, pemFile, password string) (transport.AuthMethod, error) {
+	return ssh.NewPublicKeysFromFile(user, pemFile, password)
+}
+
+// NewClonedDatabase returns a database with the repository cloned in memory
+// from the given URL with the given options, which may be nil.
+func NewClonedDatabase(url string, opts *CloneOptions) (sql.Database, error) {
+	if opts == nil {
+		opts = &CloneOptions{}
+	}
+
+	o := &git.CloneOptions{
+		URL:          url,
+		Auth:         opts.Auth,
+		SingleBranch: opts.SingleBranch,
+		Depth:        opts.Depth,
+		Progress:     opts.Progress,
+	}
+
+	if name := opts.ReferenceName; name != "" {
+		if !strings.HasPrefix(name, "refs/") {
+			name = "refs/heads/" + name
+		}
+		o.ReferenceName = plumbing.ReferenceName(name)
+	}
+
+	r := git.NewMemoryRepository()
+	if err := r.Clone(o); err != nil {
+		return nil, fmt.Errorf("can't clone %q: %s", url, err)
+	}
+
+	return newDatabase(url, r), nil
+}
+
+// NewLocalDatabase returns a database with the repository at the given path
+// of the local filesystem, which may be prefixed with "file://". The path can
+// be a working copy, a bare repository or a .git directory. Objects are read
+// from disk as they are needed.
+func NewLocalDatabase(path string) (sql.Database, error) {
+	dir, err := gitDir(strings.TrimPrefix(path, "file://"))
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := git.NewFilesystemRepository(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDatabase(path, r), nil
+}
+
+func newDatabase(url string, r *git.Repository) *Database {
 	return &Database{
-		url: url,
-		cr: newCommitsRelation(r),
+		name: repositoryName(url),
+		cr:   newCommitsRelation(r),
+		rr:   newRefsRelation(r),
 	}
 }
 
+// repositoryName returns the name of the repository with the given URL or
+// path, which is its last element without the .git extension. Characters
+// that can't be part of an identifier are replaced with underscores.
+func repositoryName(url string) string {
+	url = strings.TrimSuffix(strings.TrimRight(url, "/"), "/.git")
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		url = url[i+1:]
+	}
+
+	name := []rune(strings.TrimSuffix(url, ".git"))
+	for i, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			name[i] = '_'
+		}
+	}
+
+	switch {
+	case len(name) == 0:
+		return "repo"
+	case !unicode.IsLetter(name[0]):
+		return "repo_" + string(name)
+	}
+	return string(name)
+}
+
+// gitDir returns the directory with the objects and references of the
+// repository at the given path, which is its .git directory for working
+// copies.
+func gitDir(path string) (string, error) {
+	if fi, err := os.Stat(filepath.Join(path, ".git")); err == nil && fi.IsDir() {
+		path = filepath.Join(path, ".git")
+	}
+
+	if _, err := os.Stat(filepath.Join(path, "HEAD")); err != nil {
+		return "", fmt.Errorf("%q is not a git repository", path)
+	}
+
+	return path, nil
+}
+
 func (d Database) Name() string {
-	return d.url
+	return d.name
 }
 
 func (d Database) Relations() map[string]sql.PhysicalRelation {
 	return map[string]sql.PhysicalRelation{
 		commitsRelationName: d.cr,
+		refsRelationName:    d.rr,
 	}
 }
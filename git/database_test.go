@@ -1,17 +1,157 @@
 package git
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/mvader/gitql/sql"
 )
 
 func TestDatabase(t *testing.T) {
 	assert := assert.New(t)
-	var db sql.Database = NewDatabase("https://github.com/smola/galimatias.git")
+	dir := newFixtureRepository(t)
+	defer os.RemoveAll(dir)
+
+	var db sql.Database = NewDatabase("file://" + dir)
 	assert.NotNil(db)
 	relations := db.Relations()
 	_, ok := relations[commitsRelationName]
 	assert.True(ok)
+	_, ok = relations[refsRelationName]
+	assert.True(ok)
+}
+
+func TestLocalDatabase(t *testing.T) {
+	assert := assert.New(t)
+	root := newFixtureRepository(t)
+	defer os.RemoveAll(root)
+
+	paths := []string{
+		root,
+		filepath.Join(root, ".git"),
+		"file://" + root,
+	}
+
+	for _, path := range paths {
+		db, err := NewLocalDatabase(path)
+		assert.Nil(err, path)
+		assert.Equal(filepath.Base(root), db.Name())
+
+		iter, err := db.Relations()[commitsRelationName].RowIter()
+		assert.Nil(err, path)
+		row, err := iter.Next()
+		assert.Nil(err, path)
+		assert.NotNil(row, path)
+	}
+
+	dir, err := ioutil.TempDir("", "gitql")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	_, err = NewLocalDatabase(dir)
+	assert.EqualError(err, fmt.Sprintf("%q is not a git repository", dir))
+}
+
+func TestRepositoryName(t *testing.T) {
+	assert := assert.New(t)
+	names := map[string]string{
+		"https://github.com/smola/galimatias.git": "galimatias",
+		"https://github.com/smola/galimatias/":    "galimatias",
+		"git@github.com:src-d/go-git.git":         "go_git",
+		"git@example.com:gitql":                   "gitql",
+		"file:///tmp/gitql/.git":                  "gitql",
+		"/tmp/2017.repo":                          "repo_2017_repo",
+		"":                                        "repo",
+	}
+
+	for url, expected := range names {
+		assert.Equal(expected, repositoryName(url), url)
+	}
+}
+
+func TestClonedDatabase(t *testing.T) {
+	require := require.New(t)
+	repo := newFixtureRepository(t)
+	defer os.RemoveAll(repo)
+
+	dir, err := ioutil.TempDir("", "gitql")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	bare := filepath.Join(dir, "bare.git")
+	out, err := exec.Command("git", "clone", "--bare", repo, bare).CombinedOutput()
+	require.Nil(err, string(out))
+
+	var progress bytes.Buffer
+	url := "file://" + bare
+	db, err := NewClonedDatabase(url, &CloneOptions{
+		ReferenceName: fixtureBranch,
+		SingleBranch:  true,
+		Depth:         1,
+		Progress:      &progress,
+	})
+	require.Nil(err)
+	require.Equal("bare", db.Name())
+
+	iter, err := db.Relations()[commitsRelationName].RowIter()
+	require.Nil(err)
+	_, err = iter.Next()
+	require.Nil(err)
+	_, err = iter.Next()
+	require.Equal(io.EOF, err)
+
+	_, err = NewClonedDatabase("file://"+filepath.Join(dir, "missing.git"), nil)
+	require.NotNil(err)
+}
+
+// fixtureBranch is the branch checked out in the repositories created by
+// newFixtureRepository.
+const fixtureBranch = "fixture"
+
+// newFixtureRepository creates a working copy in a temporary directory with
+// two commits in fixtureBranch, the lightweight tag "v1.0.0" pointing to the
+// first one and the annotated tag "v2.0.0" pointing to the second one.
+// The caller must remove the directory.
+func newFixtureRepository(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "gitql")
+	require.Nil(t, err)
+
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"HOME="+dir,
+			"GIT_CONFIG_NOSYSTEM=1",
+			"GIT_AUTHOR_NAME=gitql",
+			"GIT_AUTHOR_EMAIL=gitql@example.com",
+			"GIT_COMMITTER_NAME=gitql",
+			"GIT_COMMITTER_EMAIL=gitql@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		require.Nil(t, err, string(out))
+	}
+
+	commit := func(msg string) {
+		file := filepath.Join(dir, "README")
+		require.Nil(t, ioutil.WriteFile(file, []byte(msg+"\n"), 0644))
+		git("add", "README")
+		git("commit", "-q", "-m", msg)
+	}
+
+	git("init", "-q")
+	git("symbolic-ref", "HEAD", "refs/heads/"+fixtureBranch)
+	commit("first")
+	git("tag", "v1.0.0")
+	commit("second")
+	git("tag", "-a", "-m", "second release", "v2.0.0")
+
+	return dir
 }
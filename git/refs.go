@@ -0,0 +1,140 @@
+package git
+
+import (
+	"io"
+
+	"github.com/mvader/gitql/sql"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+)
+
+// Types of the references in the refs relation.
+const (
+	refTypeBranch = "branch"
+	refTypeTag    = "tag"
+	refTypeRemote = "remote"
+	refTypeHEAD   = "HEAD"
+	refTypeOther  = "other"
+)
+
+type refsRelation struct {
+	r *git.Repository
+}
+
+func newRefsRelation(r *git.Repository) sql.PhysicalRelation {
+	return &refsRelation{r: r}
+}
+
+func (refsRelation) Name() string {
+	return refsRelationName
+}
+
+// Schema returns the schema of the relation. The hash of a symbolic
+// reference is the one of the reference it points to, which is in target,
+// and it is NULL if that reference does not exist. The commit_hash is the
+// commit the reference points to once annotated tags are peeled, so it can be
+// joined with the hash of the commits, and it is NULL if the reference does
+// not point to a commit.
+func (refsRelation) Schema() sql.Schema {
+	return sql.Schema{
+		sql.Field{Name: "name", Type: sql.String, Source: refsRelationName},
+		sql.Field{Name: "type", Type: sql.String, Source: refsRelationName},
+		sql.Field{Name: "hash", Type: sql.String, Nullable: true, Source: refsRelationName},
+		sql.Field{Name: "is_symbolic", Type: sql.Boolean, Source: refsRelationName},
+		sql.Field{Name: "target", Type: sql.String, Nullable: true, Source: refsRelationName},
+		sql.Field{Name: "commit_hash", Type: sql.String, Nullable: true, Source: refsRelationName},
+	}
+}
+
+func (r refsRelation) RowIter() (sql.RowIter, error) {
+	rIter, err := r.r.Refs()
+	if err != nil {
+		return nil, err
+	}
+	return &refsIter{r: r.r, i: rIter}, nil
+}
+
+func (refsRelation) Children() []sql.Node {
+	return []sql.Node{}
+}
+
+func (r *refsRelation) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	return f(r)
+}
+
+func (r *refsRelation) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	return r, nil
+}
+
+type refsIter struct {
+	r *git.Repository
+	i storer.ReferenceIter
+}
+
+func (i *refsIter) Next() (sql.Row, error) {
+	ref, err := i.i.Next()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	return i.refToRow(ref), nil
+}
+
+func (i *refsIter) refToRow(ref *plumbing.Reference) sql.Row {
+	var hash, target, commitHash interface{}
+	symbolic := ref.Type() == plumbing.SymbolicReference
+	if symbolic {
+		target = ref.Target().String()
+		if resolved, err := i.r.Reference(ref.Name(), true); err == nil {
+			hash = resolved.Hash().String()
+			commitHash = i.commitHash(resolved.Hash())
+		}
+	} else {
+		hash = ref.Hash().String()
+		commitHash = i.commitHash(ref.Hash())
+	}
+
+	return sql.NewMemoryRow(
+		ref.Name().String(),
+		refType(ref.Name()),
+		hash,
+		symbolic,
+		target,
+		commitHash,
+	)
+}
+
+// commitHash returns the hash of the commit the object with the given hash
+// points to, following annotated tags, or nil if it is not a commit.
+func (i *refsIter) commitHash(h plumbing.Hash) interface{} {
+	for {
+		tag, err := i.r.Tag(h)
+		if err != nil {
+			break
+		}
+		h = tag.Target
+	}
+
+	if _, err := i.r.Commit(h); err != nil {
+		return nil
+	}
+	return h.String()
+}
+
+func refType(name plumbing.ReferenceName) string {
+	switch {
+	case name == plumbing.HEAD:
+		return refTypeHEAD
+	case name.IsBranch():
+		return refTypeBranch
+	case name.IsTag():
+		return refTypeTag
+	case name.IsRemote():
+		return refTypeRemote
+	default:
+		return refTypeOther
+	}
+}
@@ -0,0 +1,110 @@
+package git
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mvader/gitql/parse"
+	"github.com/mvader/gitql/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefsRelation(t *testing.T) {
+	require := require.New(t)
+	dir := newFixtureRepository(t)
+	defer os.RemoveAll(dir)
+
+	db, err := NewLocalDatabase(dir)
+	require.Nil(err)
+	rel, ok := db.Relations()[refsRelationName]
+	require.True(ok)
+	require.Equal(refsRelationName, rel.Name())
+	require.Equal(0, len(rel.Children()))
+
+	iter, err := rel.RowIter()
+	require.Nil(err)
+
+	refs := map[string]sql.Row{}
+	for {
+		row, err := iter.Next()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(err)
+		require.Equal(len(rel.Schema()), len(row.Fields()))
+		refs[row.Fields()[0].(string)] = row
+	}
+
+	head, ok := refs["HEAD"]
+	require.True(ok)
+	fields := head.Fields()
+	require.Equal(refTypeHEAD, fields[1])
+	require.Equal(true, fields[3])
+
+	require.Equal("refs/heads/"+fixtureBranch, fields[4])
+
+	target, ok := refs[fields[4].(string)]
+	require.True(ok)
+	require.Equal(refTypeBranch, target.Fields()[1])
+	require.Equal(false, target.Fields()[3])
+	require.Nil(target.Fields()[4])
+	require.Equal(target.Fields()[2], fields[2])
+
+	require.Equal(target.Fields()[2], target.Fields()[5])
+	require.Equal(target.Fields()[2], fields[5])
+
+	tag, ok := refs["refs/tags/v1.0.0"]
+	require.True(ok)
+	require.Equal(refTypeTag, tag.Fields()[1])
+	require.NotEqual(target.Fields()[2], tag.Fields()[2])
+	require.Equal(tag.Fields()[2], tag.Fields()[5])
+
+	annotated, ok := refs["refs/tags/v2.0.0"]
+	require.True(ok)
+	require.Equal(refTypeTag, annotated.Fields()[1])
+	require.NotEqual(target.Fields()[2], annotated.Fields()[2])
+	require.Equal(target.Fields()[2], annotated.Fields()[5])
+
+	require.Equal(4, len(refs))
+}
+
+func TestRefsRelation_JoinCommits(t *testing.T) {
+	require := require.New(t)
+	dir := newFixtureRepository(t)
+	defer os.RemoveAll(dir)
+
+	db, err := NewLocalDatabase(dir)
+	require.Nil(err)
+
+	node, err := parse.Parse(db, strings.NewReader(
+		`SELECT refs.name, commits.message FROM refs
+		INNER JOIN commits ON refs.commit_hash = commits.hash
+		WHERE refs.type = 'tag' ORDER BY refs.name`,
+	))
+	require.Nil(err)
+
+	iter, err := node.RowIter()
+	require.Nil(err)
+
+	var rows []sql.Row
+	for {
+		row, err := iter.Next()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(err)
+		rows = append(rows, row)
+	}
+
+	require.Equal([]sql.Row{
+		sql.NewMemoryRow("refs/tags/v1.0.0", "first\n"),
+		sql.NewMemoryRow("refs/tags/v2.0.0", "second\n"),
+	}, rows)
+
+	_, err = parse.Parse(db, strings.NewReader(
+		"SELECT r.name FROM "+db.Name()+".refs r WHERE r.type = 'tag'",
+	))
+	require.Nil(err)
+}
@@ -13,10 +13,18 @@ type Table struct {
 	data [][]interface{}
 }
 
+// NewTable returns an empty table with the given schema. The source of every
+// field of the schema is set to the name of the table.
 func NewTable(name string, schema sql.Schema) *Table {
+	var sourced sql.Schema
+	for _, f := range schema {
+		f.Source = name
+		sourced = append(sourced, f)
+	}
+
 	return &Table{
 		name: name,
-		schema: schema,
+		schema: sourced,
 		data: [][]interface{}{},
 	}
 }
@@ -33,6 +41,14 @@ func (t *Table) Children() []sql.Node {
 	return []sql.Node{}
 }
 
+func (t *Table) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	return f(t)
+}
+
+func (t *Table) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	return t, nil
+}
+
 func (t *Table) RowIter() (sql.RowIter, error) {
 	return &iter{data: t.data}, nil
 }
@@ -43,6 +59,12 @@ func (t *Table) Insert(values ...interface{}) error {
 	}
 	for idx, value := range values {
 		f := t.schema[idx]
+		if value == nil {
+			if !f.Nullable {
+				return fmt.Errorf("field %q can't be null", f.Name)
+			}
+			continue
+		}
 		if !f.Type.Check(value) {
 			return sql.ErrInvalidType
 		}
@@ -11,7 +11,7 @@ import (
 func TestTable_Name(t *testing.T) {
 	assert := assert.New(t)
 	s := sql.Schema{
-		sql.Field{"col1", sql.String},
+		sql.Field{Name: "col1", Type: sql.String},
 	}
 	table := NewTable("test", s)
 	assert.Equal("test", table.Name())
@@ -20,7 +20,7 @@ func TestTable_Name(t *testing.T) {
 func TestTable_Insert_RowIter(t *testing.T) {
 	assert := assert.New(t)
 	s := sql.Schema{
-		sql.Field{"col1", sql.String},
+		sql.Field{Name: "col1", Type: sql.String},
 	}
 	table := NewTable("test", s)
 	iter, err := table.RowIter()
@@ -43,3 +43,14 @@ func TestTable_Insert_RowIter(t *testing.T) {
 	assert.Nil(row)
 	assert.Equal(io.EOF, err)
 }
+
+func TestTable_Insert_Null(t *testing.T) {
+	assert := assert.New(t)
+	s := sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+		sql.Field{Name: "col2", Type: sql.String, Nullable: true},
+	}
+	table := NewTable("test", s)
+	assert.Nil(table.Insert("foo", nil))
+	assert.NotNil(table.Insert(nil, "foo"))
+}
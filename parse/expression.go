@@ -1,6 +1,8 @@
 package parse
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -10,30 +12,88 @@ import (
 
 func assembleExpression(s *tokenStack) (sql.Expression, error) {
 	tk := s.pop()
+	if tk == nil {
+		return nil, errors.New("expecting expression")
+	}
 
 	switch tk.Type {
 	case OpToken:
 		op := strings.ToLower(tk.Value)
-		var left, right sql.Expression
+		var left, right, escape sql.Expression
 		var err error
+		if op == "like" || op == "not like" {
+			if t := s.peek(); t != nil && t.Type == OpToken && kwMatches(t.Value, "escape") {
+				s.pop()
+				escape, err = assembleExpression(s)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
 		right, err = assembleExpression(s)
 		if err != nil {
 			return nil, err
 		}
 
-		if op != "not" {
+		if op != "not" && op != "-u" {
 			left, err = assembleExpression(s)
 			if err != nil {
 				return nil, err
 			}
 		}
 
-		switch tk.Value {
+		switch op {
 		case "not":
 			return expression.NewNot(right)
+		case "is", "is not":
+			if right.Type() != sql.Null {
+				return nil, fmt.Errorf("expecting NULL after %q", strings.ToUpper(op))
+			}
+
+			if op == "is" {
+				return expression.NewIsNull(left), nil
+			}
+			return expression.NewNot(expression.NewIsNull(left))
+		case "-u":
+			return expression.NewUnaryMinus(right), nil
+		case "+", "-", "*", "/", "%":
+			return expression.NewArithmetic(left, right, op), nil
 		case "=":
 			return expression.NewEquals(left, right), nil
+		case "<>":
+			return expression.NewNotEquals(left, right), nil
+		case ">":
+			return expression.NewGreaterThan(left, right), nil
+		case ">=":
+			return expression.NewGreaterThanOrEqual(left, right), nil
+		case "<":
+			return expression.NewLessThan(left, right), nil
+		case "<=":
+			return expression.NewLessThanOrEqual(left, right), nil
+		case "like":
+			return expression.NewLike(left, right, escape), nil
+		case "not like":
+			return expression.NewNot(expression.NewLike(left, right, escape))
+		case "regexp":
+			return expression.NewRegexp(left, right), nil
+		case "not regexp":
+			return expression.NewNot(expression.NewRegexp(left, right))
+		case "as":
+			alias, ok := right.(*expression.Identifier)
+			if !ok {
+				return nil, errors.New(`expecting alias name after "AS"`)
+			}
+			return expression.NewAlias(left, alias.Name()), nil
+		case "and":
+			return expression.NewAnd(left, right), nil
+		case "or":
+			return expression.NewOr(left, right), nil
+		case "xor":
+			return expression.NewXor(left, right), nil
 		}
+
+		return nil, fmt.Errorf("unsupported operator %q", tk.Value)
 	case IdentifierToken:
 		if kwMatches(tk.Value, "true") {
 			return expression.NewLiteral(true, sql.Boolean), nil
@@ -43,9 +103,17 @@ func assembleExpression(s *tokenStack) (sql.Expression, error) {
 			return expression.NewLiteral(false, sql.Boolean), nil
 		}
 
+		if kwMatches(tk.Value, "null") {
+			return expression.NewLiteral(nil, sql.Null), nil
+		}
+
+		if idx := strings.Index(tk.Value, "."); idx >= 0 {
+			return expression.NewQualifiedIdentifier(tk.Value[:idx], tk.Value[idx+1:]), nil
+		}
+
 		return expression.NewIdentifier(tk.Value), nil
 	case StringToken:
-		// TODO: Parse timestamp
+		// strings are converted to timestamps when compared with them
 		return expression.NewLiteral(
 			strings.Trim(tk.Value, `"'`),
 			sql.String,
@@ -54,12 +122,163 @@ func assembleExpression(s *tokenStack) (sql.Expression, error) {
 		// error is avoided because number format is known to be ok
 		n, _ := strconv.ParseInt(tk.Value, 10, 64)
 		return expression.NewLiteral(n, sql.BigInteger), nil
+	case StarToken:
+		if table := strings.TrimSuffix(tk.Value, ".*"); table != tk.Value {
+			return expression.NewQualifiedStar(table), nil
+		}
+		return expression.NewStar(), nil
+	case FunctionToken:
+		var (
+			args     []sql.Expression
+			distinct bool
+		)
+
+		for {
+			t := s.peek()
+			if t == nil {
+				return nil, fmt.Errorf("missing arguments of function %q", tk.Value)
+			}
+
+			if t.Type == LeftParenToken {
+				s.pop()
+				break
+			}
+
+			if t.Type == KeywordToken && kwMatches(t.Value, "distinct") {
+				s.pop()
+				distinct = true
+				continue
+			}
+
+			arg, err := assembleExpression(s)
+			if err != nil {
+				return nil, err
+			}
+			args = append([]sql.Expression{arg}, args...)
+		}
+
+		return assembleFunction(tk.Value, distinct, args)
 	case FloatToken:
 		// error is avoided because number format is known to be ok
 		f, _ := strconv.ParseFloat(tk.Value, 64)
 		return expression.NewLiteral(f, sql.Float), nil
+	case KeywordToken:
+		if kwMatches(tk.Value, "end") {
+			return assembleCase(s)
+		}
+
+		return nil, fmt.Errorf("expecting expression before %s", strings.ToUpper(tk.Value))
 	}
 
 	// TODO: this should not be possible
 	return nil, nil
 }
+
+// assembleCase assembles a CASE whose END has just been popped. Its
+// expressions are popped backwards, each one followed by the keyword before
+// it.
+func assembleCase(s *tokenStack) (sql.Expression, error) {
+	var (
+		expr, value, elseExpr sql.Expression
+		branches              []expression.CaseBranch
+	)
+
+	for {
+		if t := s.peek(); t != nil && t.Type == KeywordToken && kwMatches(t.Value, "case") {
+			s.pop()
+			break
+		}
+
+		e, err := assembleExpression(s)
+		if err != nil {
+			return nil, err
+		}
+
+		kw := s.pop()
+		if kw == nil || kw.Type != KeywordToken {
+			return nil, errors.New("malformed CASE expression")
+		}
+
+		switch strings.ToLower(kw.Value) {
+		case "else":
+			if elseExpr != nil || value != nil || len(branches) > 0 {
+				return nil, errors.New("ELSE must be the last part of CASE")
+			}
+			elseExpr = e
+			continue
+		case "then":
+			if value != nil {
+				return nil, errors.New(`expecting WHEN before THEN`)
+			}
+			value = e
+			continue
+		case "when":
+			if value == nil {
+				return nil, errors.New(`expecting THEN after WHEN`)
+			}
+			branches = append([]expression.CaseBranch{{Cond: e, Value: value}}, branches...)
+			value = nil
+			continue
+		case "case":
+			expr = e
+		default:
+			return nil, fmt.Errorf("unexpected %s in CASE", strings.ToUpper(kw.Value))
+		}
+
+		break
+	}
+
+	if value != nil {
+		return nil, errors.New(`expecting WHEN before THEN`)
+	}
+
+	if len(branches) == 0 {
+		return nil, errors.New(`expecting WHEN in CASE`)
+	}
+
+	return expression.NewCase(expr, branches, elseExpr), nil
+}
+
+func assembleFunction(name string, distinct bool, args []sql.Expression) (sql.Expression, error) {
+	if kwMatches(name, "cast") && !distinct {
+		return assembleCast(args)
+	}
+
+	if !distinct {
+		return expression.DefaultRegistry.Call(name, args...)
+	}
+
+	if !kwMatches(name, "count") {
+		return nil, fmt.Errorf("DISTINCT is not supported in function %q", name)
+	}
+
+	if len(args) != 1 {
+		return nil, fmt.Errorf("function %q expects 1 argument, %d received", name, len(args))
+	}
+
+	if _, ok := args[0].(*expression.Star); ok {
+		return nil, errors.New(`"*" is not a valid argument for COUNT(DISTINCT)`)
+	}
+
+	return expression.NewCountDistinct(args[0]), nil
+}
+
+// assembleCast assembles a CAST, whose only argument is parsed as an alias
+// of the expression to convert whose name is the type.
+func assembleCast(args []sql.Expression) (sql.Expression, error) {
+	var alias *expression.Alias
+	if len(args) == 1 {
+		alias, _ = args[0].(*expression.Alias)
+	}
+
+	if alias == nil {
+		return nil, errors.New("expecting CAST(expression AS type)")
+	}
+
+	t, err := sql.TypeByName(alias.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return expression.NewCast(alias.Child(), t), nil
+}
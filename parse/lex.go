@@ -174,7 +174,9 @@ func lexLine(l *Lexer) (stateFunc, error) {
 func scanDigits(l *Lexer) error {
 	for {
 		r, err := l.next()
-		if err != nil {
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
 			return err
 		}
 
@@ -190,7 +192,10 @@ func lexNumber(l *Lexer) (stateFunc, error) {
 	}
 
 	r, err := l.next()
-	if err != nil {
+	if err == io.EOF {
+		l.emit(IntToken)
+		return lexLine, nil
+	} else if err != nil {
 		return nil, err
 	}
 
@@ -201,7 +206,10 @@ func lexNumber(l *Lexer) (stateFunc, error) {
 		}
 
 		r, err := l.next()
-		if err != nil {
+		if err == io.EOF {
+			l.emit(FloatToken)
+			return lexLine, nil
+		} else if err != nil {
 			return nil, err
 		}
 
@@ -228,6 +236,8 @@ func lexNumber(l *Lexer) (stateFunc, error) {
 var keywords = []string{
 	"select", "from", "where", "in", "order", "by", "asc", "like",
 	"desc", "and", "or", "distinct", "limit", "offset", "as", "xor",
+	"group", "having", "join", "inner", "left", "outer", "cross", "on",
+	"not", "is", "regexp", "escape", "case", "when", "then", "else", "end",
 }
 
 func isKeyword(kw string) bool {
@@ -243,7 +253,10 @@ func isKeyword(kw string) bool {
 func lexIdentifier(l *Lexer) (stateFunc, error) {
 	for {
 		r, err := l.next()
-		if err != nil {
+		if err == io.EOF {
+			emitIdentifier(l)
+			return lexLine, nil
+		} else if err != nil {
 			return nil, err
 		}
 
@@ -252,18 +265,21 @@ func lexIdentifier(l *Lexer) (stateFunc, error) {
 				return nil, err
 			}
 
-			word := l.peekWord()
-			var typ = IdentifierToken
-			if isKeyword(word) {
-				typ = KeywordToken
-			}
-
-			l.emit(typ)
+			emitIdentifier(l)
 			return lexLine, nil
 		}
 	}
 }
 
+func emitIdentifier(l *Lexer) {
+	var typ = IdentifierToken
+	if isKeyword(l.peekWord()) {
+		typ = KeywordToken
+	}
+
+	l.emit(typ)
+}
+
 var operators = []string{
 	"<", ">", ">=", "<=", "=", "<>",
 	"+", "-", "*", "/", "%",
@@ -281,7 +297,9 @@ func isValidOperator(word string) bool {
 func lexOp(l *Lexer) (stateFunc, error) {
 	for {
 		r, err := l.next()
-		if err != nil {
+		if err == io.EOF {
+			return emitOp(l), nil
+		} else if err != nil {
 			return nil, err
 		}
 
@@ -290,17 +308,21 @@ func lexOp(l *Lexer) (stateFunc, error) {
 				return nil, err
 			}
 
-			op := l.peekWord()
-			if !isValidOperator(op) {
-				return l.errorf("invalid operator: %q", op), nil
-			}
-
-			l.emit(OpToken)
-			return lexLine, nil
+			return emitOp(l), nil
 		}
 	}
 }
 
+func emitOp(l *Lexer) stateFunc {
+	op := l.peekWord()
+	if !isValidOperator(op) {
+		return l.errorf("invalid operator: %q", op)
+	}
+
+	l.emit(OpToken)
+	return lexLine
+}
+
 func lexQuote(l *Lexer) (stateFunc, error) {
 	return lexString(l, quote)
 }
@@ -313,7 +335,9 @@ func lexString(l *Lexer, quoteRune rune) (stateFunc, error) {
 	var escaped bool
 	for {
 		r, err := l.next()
-		if err != nil {
+		if err == io.EOF {
+			return l.errorf("unterminated string: %s", l.peekWord()), nil
+		} else if err != nil {
 			return nil, err
 		}
 
@@ -44,7 +44,7 @@ func TestLexQuote(t *testing.T) {
 		{`foo bar", `, `foo bar"`, StringToken},
 		{`foo \tar", `, `foo \tar"`, StringToken},
 		{`foo \"\"bar", `, `foo \"\"bar"`, StringToken},
-		{`foo bar`, ``, EOFToken},
+		{`foo bar`, ``, ErrorToken},
 	}
 
 	testLex(t, cases, lexQuote)
@@ -55,7 +55,7 @@ func TestLexSingleQuote(t *testing.T) {
 		{`foo bar', `, `foo bar'`, StringToken},
 		{`foo \tar', `, `foo \tar'`, StringToken},
 		{`foo \'\'bar', `, `foo \'\'bar'`, StringToken},
-		{`foo bar`, ``, EOFToken},
+		{`foo bar`, ``, ErrorToken},
 	}
 
 	testLex(t, cases, lexSingleQuote)
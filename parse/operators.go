@@ -30,24 +30,29 @@ func (o *operator) comparePrecedence(o2 *operator) int {
 }
 
 var opTable = map[string]*operator{
-	"not":  newOperator("not", RightAssoc, 7),
-	"-u":   newOperator("-", RightAssoc, 7), // unary minus
-	"+":    newOperator("+", LeftAssoc, 6),
-	"-":    newOperator("-", LeftAssoc, 6),
-	"/":    newOperator("/", LeftAssoc, 6),
-	"*":    newOperator("*", LeftAssoc, 6),
-	"%":    newOperator("%", LeftAssoc, 6),
-	">":    newOperator(">", LeftAssoc, 5),
-	">=":   newOperator(">=", LeftAssoc, 5),
-	"<":    newOperator("<", LeftAssoc, 5),
-	"<=":   newOperator("<=", LeftAssoc, 5),
-	"=":    newOperator("=", LeftAssoc, 5),
-	"<>":   newOperator("<>", LeftAssoc, 5),
-	"like": newOperator("like", LeftAssoc, 5),
-	"is":   newOperator("is", LeftAssoc, 5),
-	"as":   newOperator("as", LeftAssoc, 5),
-	"in":   newOperator("in", LeftAssoc, 5),
-	"and":  newOperator("and", LeftAssoc, 4),
-	"xor":  newOperator("xor", LeftAssoc, 4),
-	"or":   newOperator("or", LeftAssoc, 4),
+	"-u":         newOperator("-", RightAssoc, 10), // unary minus
+	"/":          newOperator("/", LeftAssoc, 9),
+	"*":          newOperator("*", LeftAssoc, 9),
+	"%":          newOperator("%", LeftAssoc, 9),
+	"+":          newOperator("+", LeftAssoc, 8),
+	"-":          newOperator("-", LeftAssoc, 8),
+	"escape":     newOperator("escape", LeftAssoc, 7),
+	">":          newOperator(">", LeftAssoc, 6),
+	">=":         newOperator(">=", LeftAssoc, 6),
+	"<":          newOperator("<", LeftAssoc, 6),
+	"<=":         newOperator("<=", LeftAssoc, 6),
+	"=":          newOperator("=", LeftAssoc, 6),
+	"<>":         newOperator("<>", LeftAssoc, 6),
+	"like":       newOperator("like", LeftAssoc, 6),
+	"not like":   newOperator("not like", LeftAssoc, 6),
+	"regexp":     newOperator("regexp", LeftAssoc, 6),
+	"not regexp": newOperator("not regexp", LeftAssoc, 6),
+	"is":         newOperator("is", LeftAssoc, 6),
+	"is not":     newOperator("is not", LeftAssoc, 6),
+	"in":         newOperator("in", LeftAssoc, 6),
+	"not":        newOperator("not", RightAssoc, 5),
+	"and":        newOperator("and", LeftAssoc, 4),
+	"xor":        newOperator("xor", LeftAssoc, 3),
+	"or":         newOperator("or", LeftAssoc, 2),
+	"as":         newOperator("as", LeftAssoc, 1),
 }
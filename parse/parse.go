@@ -4,9 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/mvader/gitql/sql"
+	"github.com/mvader/gitql/sql/analyzer"
+	"github.com/mvader/gitql/sql/expression"
 	"github.com/mvader/gitql/sql/plan"
 )
 
@@ -21,15 +24,73 @@ const (
 	FromListState
 	WhereState
 	WhereClauseState
+	GroupState
+	GroupByState
+	HavingState
+	HavingClauseState
 	OrderState
 	OrderByState
 	OrderClauseState
+	LimitState
+	LimitClauseState
+	OffsetState
+	OffsetClauseState
 	DoneState
 
 	ExprState
 	ExprEndState
 )
 
+type clause struct {
+	keyword string
+	state   ParseState
+}
+
+// clauses are the clauses of a query that follow the projection, in the
+// order in which they must appear.
+var clauses = []clause{
+	{"from", FromState},
+	{"where", WhereState},
+	{"group", GroupState},
+	{"having", HavingState},
+	{"order", OrderState},
+	{"limit", LimitState},
+	{"offset", OffsetState},
+}
+
+// clausesAfter returns the clauses that may appear after the given state.
+// FROM is the only clause that is mandatory, so it is the only one that can
+// follow the projection. LIMIT and OFFSET may appear in any order, but only
+// once each.
+func (p *parser) clausesAfter(state ParseState) []clause {
+	if state == SelectState {
+		return clauses[:1]
+	}
+
+	switch {
+	case state == OffsetState && p.limit == nil:
+		return []clause{{"limit", LimitState}}
+	case state == LimitState && p.offset != nil:
+		return nil
+	}
+
+	for i, c := range clauses {
+		if c.state == state {
+			return clauses[i+1:]
+		}
+	}
+
+	return nil
+}
+
+func clauseKeywords(cs []clause) string {
+	var kws []string
+	for _, c := range cs {
+		kws = append(kws, fmt.Sprintf("%q", c.keyword))
+	}
+	return strings.Join(kws, " or ")
+}
+
 type parser struct {
 	prevState  ParseState
 	stateStack *stateStack
@@ -38,10 +99,16 @@ type parser struct {
 	opStack    *tokenStack
 	err        error
 
+	distinct      bool
 	projection    []sql.Expression
 	relations     []sql.Expression
+	joins         []join
 	filterClauses []sql.Expression
-	orderClauses  []sql.Expression
+	groupBy       []sql.Expression
+	havingClauses []sql.Expression
+	orderClauses  []plan.SortField
+	limit         *int64
+	offset        *int64
 }
 
 func newParser(input io.Reader) *parser {
@@ -59,6 +126,12 @@ func (p *parser) parse() error {
 		return err
 	}
 
+	for _, t := range p.lexer.tokens {
+		if t.Type == ErrorToken {
+			return errors.New(t.Value)
+		}
+	}
+
 	for state := p.stateStack.peek(); state != DoneState && state != ErrorState; state = p.stateStack.peek() {
 		p.prevState = state
 		var t *Token
@@ -80,6 +153,8 @@ func (p *parser) parse() error {
 				p.errorf("expecting select field list expression, nothing received")
 			} else if t.Type == KeywordToken && kwMatches(t.Value, "from") {
 				p.errorf(`unexpected "FROM", expecting select field list expression`)
+			} else if t.Type == KeywordToken && kwMatches(t.Value, "distinct") && !p.distinct {
+				p.distinct = true
 			} else {
 				p.lexer.Backup()
 				p.stateStack.pop()
@@ -99,11 +174,14 @@ func (p *parser) parse() error {
 			case SelectState:
 				p.projection = append(p.projection, expr)
 
-			case FromState:
-				p.relations = append(p.relations, expr)
-
 			case WhereState:
 				p.filterClauses = append(p.filterClauses, expr)
+
+			case GroupState:
+				p.groupBy = append(p.groupBy, expr)
+
+			case HavingState:
+				p.havingClauses = append(p.havingClauses, expr)
 			}
 
 			p.stateStack.put(ExprEndState)
@@ -111,52 +189,20 @@ func (p *parser) parse() error {
 		case ExprEndState:
 			t = p.lexer.Next()
 			p.stateStack.pop()
-			state := p.stateStack.peek()
-			var (
-				breakKeyword string
-				nextState    ParseState
-			)
-
-			switch state {
-			case SelectState:
-				breakKeyword = "from"
-				nextState = FromState
-			case FromState:
-				breakKeyword = "where"
-				nextState = WhereState
-			case WhereState:
-				breakKeyword = "order"
-				nextState = OrderState
-			default:
-				p.errorf(`unexpected token %q`, t.Value)
-				break
+			if t != nil && t.Type == CommaToken {
+				p.stateStack.put(ExprState)
+				break OuterSwitch
 			}
 
-			if t != nil {
-				switch t.Type {
-				case CommaToken:
-					p.stateStack.put(ExprState)
-					break OuterSwitch
-				case KeywordToken:
-					if kwMatches(t.Value, breakKeyword) {
-						p.lexer.Backup()
-						p.stateStack.pop()
-						p.stateStack.put(nextState)
-						break OuterSwitch
-					}
-				case EOFToken:
-					p.stateStack.pop()
-					p.stateStack.put(DoneState)
-					break OuterSwitch
+			if !p.nextClause(t) {
+				next := p.clausesAfter(p.stateStack.peek())
+				if len(next) > 0 {
+					p.errorf(`expecting "," or %s`, clauseKeywords(next))
+				} else {
+					p.errorf(`expecting "," or end of sentence`)
 				}
 			}
 
-			if breakKeyword != "" {
-				p.errorf(`expecting "," or %q`, breakKeyword)
-			} else {
-				p.errorf(`expecting "," or end of sentence`)
-			}
-
 		case FromState:
 			t = p.lexer.Next()
 			if t == nil || t.Type == EOFToken {
@@ -168,13 +214,14 @@ func (p *parser) parse() error {
 			}
 
 		case FromListState:
-			t = p.lexer.Next()
-			if t == nil || t.Type == EOFToken {
-				p.errorf("expecting from expression, nothing received")
+			relations, joins, err := parseFromClause(p.lexer)
+			if err != nil {
+				p.error(err)
 			} else {
-				p.lexer.Backup()
+				p.relations = relations
+				p.joins = joins
 				p.stateStack.pop()
-				p.stateStack.put(ExprState)
+				p.endClause()
 			}
 
 		case WhereState:
@@ -198,6 +245,43 @@ func (p *parser) parse() error {
 				p.stateStack.put(ExprState)
 			}
 
+		case GroupState:
+			t = p.lexer.Next()
+			if t == nil || t.Type != KeywordToken || !kwMatches(t.Value, "group") {
+				p.errorf("expecting 'GROUP'")
+			} else {
+				p.stateStack.put(GroupByState)
+			}
+
+		case GroupByState:
+			t = p.lexer.Next()
+			if t == nil || t.Type == EOFToken {
+				p.errorf(`expecting "BY", nothing received`)
+			} else if t.Type != KeywordToken || !kwMatches(t.Value, "by") {
+				p.errorf("expecting 'BY', %q received", t.Value)
+			} else {
+				p.stateStack.pop()
+				p.stateStack.put(ExprState)
+			}
+
+		case HavingState:
+			t = p.lexer.Next()
+			if t == nil || t.Type != KeywordToken || !kwMatches(t.Value, "having") {
+				p.errorf("expecting 'HAVING'")
+			} else {
+				p.stateStack.put(HavingClauseState)
+			}
+
+		case HavingClauseState:
+			t = p.lexer.Next()
+			if t == nil || t.Type == EOFToken {
+				p.errorf("expecting having clause, nothing received")
+			} else {
+				p.lexer.Backup()
+				p.stateStack.pop()
+				p.stateStack.put(ExprState)
+			}
+
 		case OrderState:
 			t = p.lexer.Next()
 			if t == nil || t.Type == EOFToken {
@@ -226,7 +310,44 @@ func (p *parser) parse() error {
 			} else {
 				p.orderClauses = clauses
 				p.stateStack.pop()
-				p.stateStack.put(DoneState)
+				p.stateStack.pop()
+				p.endClause()
+			}
+
+		case LimitState:
+			t = p.lexer.Next()
+			if t == nil || t.Type != KeywordToken || !kwMatches(t.Value, "limit") {
+				p.errorf("expecting 'LIMIT'")
+			} else {
+				p.stateStack.put(LimitClauseState)
+			}
+
+		case LimitClauseState:
+			n, err := parseInt(p.lexer)
+			if err != nil {
+				p.error(err)
+			} else {
+				p.limit = &n
+				p.stateStack.pop()
+				p.endClause()
+			}
+
+		case OffsetState:
+			t = p.lexer.Next()
+			if t == nil || t.Type != KeywordToken || !kwMatches(t.Value, "offset") {
+				p.errorf("expecting 'OFFSET'")
+			} else {
+				p.stateStack.put(OffsetClauseState)
+			}
+
+		case OffsetClauseState:
+			n, err := parseInt(p.lexer)
+			if err != nil {
+				p.error(err)
+			} else {
+				p.offset = &n
+				p.stateStack.pop()
+				p.endClause()
 			}
 		}
 	}
@@ -234,26 +355,267 @@ func (p *parser) parse() error {
 	return nil
 }
 
-func (p *parser) buildTree() *plan.Project {
-	/*var fields []string
-	if p.projection.typ == projectionFields {
-		for _, f := range p.projection.fields {
-			fields = append(fields, f.name)
+// nextClause replaces the clause at the top of the state stack with the one
+// started by the given token, or with DoneState if the token ends the query.
+// It returns false if the token does not start any of the clauses that may
+// follow the current one.
+func (p *parser) nextClause(t *Token) bool {
+	if t == nil {
+		return false
+	}
+
+	switch t.Type {
+	case KeywordToken:
+		for _, c := range p.clausesAfter(p.stateStack.peek()) {
+			if kwMatches(t.Value, c.keyword) {
+				p.lexer.Backup()
+				p.stateStack.pop()
+				p.stateStack.put(c.state)
+				return true
+			}
 		}
+	case EOFToken:
+		p.stateStack.pop()
+		p.stateStack.put(DoneState)
+		return true
 	}
 
-	// TODO: build child
-	return plan.NewProject(fields, nil)*/
-	return nil
+	return false
+}
+
+// endClause moves to the next clause after a clause that is not a list of
+// expressions has been fully consumed.
+func (p *parser) endClause() {
+	t := p.lexer.Next()
+	if p.nextClause(t) {
+		return
+	}
+
+	next := p.clausesAfter(p.stateStack.peek())
+	if t == nil {
+		p.errorf("expecting end of sentence, nothing received")
+	} else if len(next) > 0 {
+		p.errorf("expecting %s or end of sentence, %q received", clauseKeywords(next), t.Value)
+	} else {
+		p.errorf("expecting end of sentence, %q received", t.Value)
+	}
+}
+
+// relation returns the relation of the catalog named by the given
+// identifier, which is qualified with the name of its database unless it is
+// in the current one, or an alias of one.
+func relation(catalog *sql.Catalog, expr sql.Expression) (sql.Node, error) {
+	if alias, ok := expr.(*expression.Alias); ok {
+		rel, err := relation(catalog, alias.Child())
+		if err != nil {
+			return nil, err
+		}
+
+		return plan.NewTableAlias(alias.Name(), rel), nil
+	}
+
+	var (
+		db  sql.Database
+		err error
+	)
+	if i, ok := expr.(*expression.Identifier); ok && i.Table() != "" {
+		db, err = catalog.Database(i.Table())
+	} else {
+		db, err = catalog.CurrentDatabase()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	name := expr.Name()
+	rel, ok := db.Relations()[name]
+	if !ok {
+		return nil, fmt.Errorf("relation %q not found in database %q", name, db.Name())
+	}
+
+	return rel, nil
+}
+
+func (p *parser) buildPlan(catalog *sql.Catalog) (sql.Node, error) {
+	if len(p.relations) == 0 {
+		return nil, errors.New("expecting at least one relation")
+	}
+
+	node, err := relation(catalog, p.relations[0])
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := range p.joins {
+		right, err := relation(catalog, p.relations[i+1])
+		if err != nil {
+			return nil, err
+		}
+
+		switch j.typ {
+		case crossJoin:
+			node = plan.NewCrossJoin(node, right)
+		case innerJoin:
+			node = plan.NewInnerJoin(node, right, j.cond)
+		case leftJoin:
+			node = plan.NewLeftJoin(node, right, j.cond)
+		}
+	}
+
+	for _, expr := range p.filterClauses {
+		node = plan.NewFilter(expr, node)
+	}
+
+	if len(p.groupBy) > 0 || len(p.havingClauses) > 0 ||
+		hasAggregations(p.projection) {
+		node = p.buildGroupBy(node)
+	} else {
+		if len(p.orderClauses) > 0 {
+			node = plan.NewSort(unaliasSortFields(p.orderClauses, p.projection), node)
+		}
+		node = plan.NewProject(p.projection, node)
+	}
+
+	if p.distinct {
+		node = plan.NewDistinct(node)
+	}
+
+	if p.offset != nil {
+		node = plan.NewOffset(*p.offset, node)
+	}
+
+	if p.limit != nil {
+		node = plan.NewLimit(*p.limit, node)
+	}
+
+	return node, nil
+}
+
+// buildGroupBy builds the grouping of the query, followed by the filters of
+// the HAVING clause and the sort, which are applied to the grouped rows.
+// Aggregations used in HAVING or ORDER BY that are not part of the
+// projection are also computed by the GroupBy, and removed afterwards.
+func (p *parser) buildGroupBy(child sql.Node) sql.Node {
+	var exprs []sql.Expression
+	exprs = append(exprs, p.havingClauses...)
+	for _, f := range p.orderClauses {
+		exprs = append(exprs, f.Expression)
+	}
+
+	aggregate := append([]sql.Expression{}, p.projection...)
+	for _, a := range findAggregations(exprs) {
+		if !containsName(aggregate, a.Name()) {
+			aggregate = append(aggregate, a)
+		}
+	}
+
+	var node sql.Node = plan.NewGroupBy(aggregate, p.groupBy, child)
+	for _, expr := range p.havingClauses {
+		node = plan.NewFilter(expr, node)
+	}
+
+	if len(p.orderClauses) > 0 {
+		node = plan.NewSort(p.orderClauses, node)
+	}
+
+	if len(aggregate) > len(p.projection) {
+		var projection []sql.Expression
+		for _, e := range p.projection {
+			projection = append(projection, expression.NewIdentifier(e.Name()))
+		}
+		node = plan.NewProject(projection, node)
+	}
+
+	return node
+}
+
+// unaliasSortFields replaces the identifiers of the sort fields that refer to
+// an alias of the projection with the aliased expression, as the rows are
+// sorted before they are projected.
+func unaliasSortFields(fields []plan.SortField, projection []sql.Expression) []plan.SortField {
+	aliases := map[string]sql.Expression{}
+	for _, e := range projection {
+		if a, ok := e.(*expression.Alias); ok {
+			aliases[a.Name()] = a.Child()
+		}
+	}
+
+	if len(aliases) == 0 {
+		return fields
+	}
+
+	var result []plan.SortField
+	for _, f := range fields {
+		e, _ := f.Expression.TransformUp(func(e sql.Expression) (sql.Expression, error) {
+			if i, ok := e.(*expression.Identifier); ok && i.Table() == "" {
+				if aliased, ok := aliases[i.Name()]; ok {
+					return aliased, nil
+				}
+			}
+			return e, nil
+		})
+		result = append(result, plan.SortField{Expression: e, Order: f.Order})
+	}
+	return result
+}
+
+func hasAggregations(exprs []sql.Expression) bool {
+	return len(findAggregations(exprs)) > 0
+}
+
+func findAggregations(exprs []sql.Expression) []sql.Expression {
+	var aggregations []sql.Expression
+	for _, e := range exprs {
+		e.TransformUp(func(e sql.Expression) (sql.Expression, error) {
+			if _, ok := e.(sql.Aggregation); ok {
+				aggregations = append(aggregations, e)
+			}
+			return e, nil
+		})
+	}
+	return aggregations
+}
+
+func containsName(exprs []sql.Expression, name string) bool {
+	for _, e := range exprs {
+		if e.Name() == name {
+			return true
+		}
+	}
+	return false
 }
 
-func Parse(input io.Reader) (*plan.Project, error) {
+// Parse parses the given SQL query and builds the plan that executes it
+// against the relations of the given database.
+func Parse(db sql.Database, input io.Reader) (sql.Node, error) {
+	return ParseCatalog(sql.NewCatalog(db), input)
+}
+
+// ParseCatalog parses the given SQL query and builds the plan that executes
+// it against the relations of the databases of the given catalog. The plan
+// is analyzed, so it is ready to be executed.
+func ParseCatalog(catalog *sql.Catalog, input io.Reader) (sql.Node, error) {
+	node, err := parsePlan(catalog, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return analyzer.Analyze(node)
+}
+
+// parsePlan parses the given SQL query and builds its plan without analyzing
+// it, so its identifiers are still unresolved.
+func parsePlan(catalog *sql.Catalog, input io.Reader) (sql.Node, error) {
 	p := newParser(input)
 	if err := p.parse(); err != nil {
 		return nil, err
 	}
 
-	return p.buildTree(), nil
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return p.buildPlan(catalog)
 }
 
 func LastStates(input io.Reader) (ParseState, ParseState, error) {
@@ -270,15 +632,207 @@ type tokenQueue interface {
 	Next() *Token
 }
 
-func parseOrderClause(q tokenQueue) ([]sql.Expression, error) {
-	// TODO
-	return nil, nil
+func parseOrderClause(q tokenQueue) ([]plan.SortField, error) {
+	var fields []plan.SortField
+	for {
+		expr, err := parseExpr(q)
+		if err != nil {
+			return nil, err
+		}
+
+		field := plan.SortField{Expression: expr, Order: plan.Ascending}
+		t := q.Next()
+		if t != nil && t.Type == KeywordToken {
+			if kwMatches(t.Value, "asc") {
+				t = q.Next()
+			} else if kwMatches(t.Value, "desc") {
+				field.Order = plan.Descending
+				t = q.Next()
+			}
+		}
+		fields = append(fields, field)
+
+		if t == nil {
+			return fields, nil
+		}
+
+		switch t.Type {
+		case CommaToken:
+			continue
+		case EOFToken, KeywordToken:
+			q.Backup()
+			return fields, nil
+		default:
+			return nil, fmt.Errorf(`unexpected %q, expecting ",", "ASC" or "DESC"`, t.Value)
+		}
+	}
+}
+
+type joinType byte
+
+const (
+	crossJoin joinType = iota
+	innerJoin
+	leftJoin
+)
+
+// join is the way a relation of the FROM clause is combined with the
+// relations that precede it.
+type join struct {
+	typ  joinType
+	cond sql.Expression
+}
+
+// parseFromClause parses the relations of the FROM clause, either separated
+// by commas or joined with JOIN. The returned joins are the ones of every
+// relation but the first.
+func parseFromClause(q tokenQueue) ([]sql.Expression, []join, error) {
+	rel, err := parseRelation(q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		relations = []sql.Expression{rel}
+		joins     []join
+	)
+
+	for {
+		t := q.Next()
+		if t == nil {
+			return relations, joins, nil
+		}
+
+		var typ joinType
+		if t.Type == CommaToken {
+			typ = crossJoin
+		} else {
+			var ok bool
+			typ, ok, err = parseJoinType(t, q)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if !ok {
+				q.Backup()
+				return relations, joins, nil
+			}
+		}
+
+		rel, err := parseRelation(q)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		j := join{typ: typ}
+		if typ != crossJoin {
+			t = q.Next()
+			if t == nil || t.Type != KeywordToken || !kwMatches(t.Value, "on") {
+				return nil, nil, errors.New("expecting 'ON' after joined relation")
+			}
+
+			j.cond, err = parseExpr(q)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		relations = append(relations, rel)
+		joins = append(joins, j)
+	}
+}
+
+// parseJoinType parses the keywords that start a join, the first of which is
+// the given token. It returns false if the token does not start a join.
+func parseJoinType(t *Token, q tokenQueue) (joinType, bool, error) {
+	if t.Type != KeywordToken {
+		return 0, false, nil
+	}
+
+	var typ joinType
+	switch strings.ToLower(t.Value) {
+	case "join":
+		return innerJoin, true, nil
+	case "inner":
+		typ = innerJoin
+	case "cross":
+		typ = crossJoin
+	case "left":
+		typ = leftJoin
+		if nt := q.Next(); nt == nil || nt.Type != KeywordToken || !kwMatches(nt.Value, "outer") {
+			q.Backup()
+		}
+	default:
+		return 0, false, nil
+	}
+
+	if nt := q.Next(); nt == nil || nt.Type != KeywordToken || !kwMatches(nt.Value, "join") {
+		return 0, false, fmt.Errorf("expecting 'JOIN' after %q", t.Value)
+	}
+
+	return typ, true, nil
+}
+
+func parseRelation(q tokenQueue) (sql.Expression, error) {
+	t := q.Next()
+	if t == nil || t.Type == EOFToken {
+		return nil, errors.New("expecting relation, nothing received")
+	}
+
+	if t.Type != IdentifierToken {
+		return nil, fmt.Errorf("expecting relation, %q received", t.Value)
+	}
+
+	rel := expression.NewIdentifier(t.Value)
+	t = q.Next()
+	if t != nil && t.Type == DotToken {
+		nt := q.Next()
+		if nt == nil || nt.Type != IdentifierToken {
+			return nil, fmt.Errorf("expecting relation after %q", rel.Name()+".")
+		}
+
+		rel = expression.NewQualifiedIdentifier(rel.Name(), nt.Value)
+		t = q.Next()
+	}
+
+	if t != nil && t.Type == KeywordToken && kwMatches(t.Value, "as") {
+		t = q.Next()
+		if t == nil || t.Type != IdentifierToken {
+			return nil, errors.New(`expecting alias name after "AS"`)
+		}
+	} else if t == nil || t.Type != IdentifierToken {
+		if t != nil {
+			q.Backup()
+		}
+		return rel, nil
+	}
+
+	return expression.NewAlias(rel, t.Value), nil
+}
+
+func parseInt(q tokenQueue) (int64, error) {
+	t := q.Next()
+	if t == nil || t.Type == EOFToken {
+		return 0, errors.New("expecting integer, nothing received")
+	}
+
+	if t.Type != IntToken {
+		return 0, fmt.Errorf("expecting integer, %q received", t.Value)
+	}
+
+	n, err := strconv.ParseInt(t.Value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer %q: %s", t.Value, err)
+	}
+
+	return n, nil
 }
 
 func parseExpr(q tokenQueue) (sql.Expression, error) {
 	var (
 		output = newTokenStack()
 		stack  = newTokenStack()
+		prev   *Token
 	)
 
 OuterLoop:
@@ -294,6 +848,25 @@ OuterLoop:
 
 		case IdentifierToken:
 			nt := q.Next()
+			if nt != nil && nt.Type == DotToken {
+				ct := q.Next()
+				if ct != nil && ct.Type == OpToken && ct.Value == "*" {
+					tk = NewToken(StarToken, tk.Value+".*", tk.Line, tk.Pos)
+					output.put(tk)
+					break
+				}
+
+				if ct == nil || ct.Type != IdentifierToken {
+					return nil, fmt.Errorf("expecting column name after %q", tk.Value+".")
+				}
+
+				// qualified identifiers are kept in a single token, as the
+				// lexer never puts a dot in the value of an identifier
+				tk = NewToken(IdentifierToken, tk.Value+"."+ct.Value, tk.Line, tk.Pos)
+				output.put(tk)
+				break
+			}
+
 			q.Backup()
 			if nt != nil && nt.Type == LeftParenToken {
 				tk.Type = FunctionToken
@@ -303,6 +876,11 @@ OuterLoop:
 			}
 
 		case LeftParenToken:
+			// the opening paren of a function call is also put in the output
+			// to mark where the arguments of the function start
+			if t := stack.peek(); t != nil && t.Type == FunctionToken {
+				output.put(tk)
+			}
 			stack.put(tk)
 
 		case RightParenToken:
@@ -321,6 +899,10 @@ OuterLoop:
 					break
 				}
 
+				if t.Type == KeywordToken {
+					return nil, errors.New(`expecting END of CASE`)
+				}
+
 				output.put(stack.pop())
 			}
 
@@ -340,11 +922,73 @@ OuterLoop:
 					break
 				}
 
+				if t.Type == KeywordToken {
+					return nil, errors.New(`expecting END of CASE`)
+				}
+
 				output.put(stack.pop())
 			}
 
 		case KeywordToken:
-			op := opTable[tk.Value]
+			// CASE is put in the stack like an opening paren, and in the
+			// output to mark where its expressions start
+			if kwMatches(tk.Value, "case") {
+				output.put(tk)
+				stack.put(tk)
+				break
+			}
+
+			// the rest of the keywords of a CASE separate its expressions in
+			// the output, and END closes it
+			if isCaseKeyword(tk.Value) {
+				for {
+					t := stack.peek()
+					if t == nil || t.Type != OpToken {
+						break
+					}
+					output.put(stack.pop())
+				}
+
+				t := stack.peek()
+				if t == nil || t.Type != KeywordToken {
+					q.Backup()
+					break OuterLoop
+				}
+
+				if kwMatches(tk.Value, "end") {
+					stack.pop()
+				}
+				output.put(tk)
+				break
+			}
+
+			if kwMatches(tk.Value, "distinct") && prev != nil &&
+				prev.Type == LeftParenToken && isFunctionCall(stack) {
+				output.put(tk)
+				break
+			}
+
+			if kwMatches(tk.Value, "is") {
+				nt := q.Next()
+				if nt != nil && nt.Type == KeywordToken && kwMatches(nt.Value, "not") {
+					tk.Value = "is not"
+				} else {
+					q.Backup()
+				}
+			}
+
+			// a NOT after an operand can only negate the operator after it
+			if kwMatches(tk.Value, "not") && !expectsOperand(prev) {
+				nt := q.Next()
+				if nt != nil && nt.Type == KeywordToken &&
+					(kwMatches(nt.Value, "like") || kwMatches(nt.Value, "regexp")) {
+					tk.Value = "not " + strings.ToLower(nt.Value)
+				} else {
+					return nil, errors.New(`expecting LIKE or REGEXP after "NOT"`)
+				}
+			}
+
+			op := opTable[strings.ToLower(tk.Value)]
 			if op == nil {
 				q.Backup()
 				break OuterLoop
@@ -353,14 +997,26 @@ OuterLoop:
 			tk.Type = OpToken
 			fallthrough
 		case OpToken:
-			for {
+			if tk.Value == "*" && expectsOperand(prev) {
+				tk.Type = StarToken
+				output.put(tk)
+				break
+			}
+
+			if tk.Value == "-" && expectsOperand(prev) {
+				tk.Value = "-u"
+			}
+
+			// prefix operators have no left operand, so they can't complete
+			// any of the operators in the stack
+			for !expectsOperand(prev) {
 				t := stack.peek()
 				if t == nil || t.Type != OpToken {
 					break
 				}
 
-				o1 := opTable[tk.Value]
-				o2 := opTable[t.Value]
+				o1 := opTable[strings.ToLower(tk.Value)]
+				o2 := opTable[strings.ToLower(t.Value)]
 				if o1.isLeftAssoc() && o1.comparePrecedence(o2) <= 0 ||
 					o1.isRightAssoc() && o1.comparePrecedence(o2) < 0 {
 					output.put(stack.pop())
@@ -370,6 +1026,8 @@ OuterLoop:
 			}
 			stack.put(tk)
 		}
+
+		prev = tk
 	}
 
 	for {
@@ -382,10 +1040,57 @@ OuterLoop:
 			return nil, errors.New(`missing closing ")"`)
 		}
 
+		if tk.Type == KeywordToken {
+			return nil, errors.New(`expecting END of CASE`)
+		}
+
 		output.put(tk)
 	}
 
-	return assembleExpression(output)
+	expr, err := assembleExpression(output)
+	if err != nil {
+		return nil, err
+	}
+
+	if !output.isEmpty() {
+		return nil, fmt.Errorf("unexpected %q in expression", output.peek().Value)
+	}
+
+	return expr, nil
+}
+
+// expectsOperand reports whether the token following the given one must be
+// an operand instead of an operator.
+func expectsOperand(prev *Token) bool {
+	if prev == nil {
+		return true
+	}
+
+	switch prev.Type {
+	case LeftParenToken, CommaToken, OpToken:
+		return true
+	case KeywordToken:
+		return !kwMatches(prev.Value, "end")
+	}
+
+	return false
+}
+
+func isCaseKeyword(kw string) bool {
+	for _, k := range []string{"when", "then", "else", "end"} {
+		if kwMatches(kw, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFunctionCall reports whether the paren at the top of the stack opens the
+// arguments of a function call.
+func isFunctionCall(stack *tokenStack) bool {
+	s := *stack
+	return len(s) >= 2 && s[len(s)-1].Type == LeftParenToken &&
+		s[len(s)-2].Type == FunctionToken
 }
 
 func (p *parser) errorf(msg string, args ...interface{}) {
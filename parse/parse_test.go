@@ -1,11 +1,15 @@
 package parse
 
 import (
+	"io"
 	"strings"
 	"testing"
 
+	"github.com/mvader/gitql/mem"
 	"github.com/mvader/gitql/sql"
+	"github.com/mvader/gitql/sql/analyzer"
 	"github.com/mvader/gitql/sql/expression"
+	"github.com/mvader/gitql/sql/plan"
 	"github.com/stretchr/testify/require"
 )
 
@@ -35,3 +39,883 @@ func TestParseSelectFromWhere(t *testing.T) {
 	require.Nil(t, p.err)
 	require.Equal(t, DoneState, p.stateStack.pop())
 }
+
+func TestParse(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	table := mem.NewTable("foo", sql.Schema{
+		sql.Field{Name: "foo", Type: sql.String},
+		sql.Field{Name: "bar", Type: sql.String},
+	})
+	db.AddTable("foo", table)
+
+	node, err := parsePlan(sql.NewCatalog(db), strings.NewReader(testSelect))
+	require.Nil(err)
+	require.Equal(plan.NewProject(
+		[]sql.Expression{
+			expression.NewIdentifier("foo"),
+			expression.NewIdentifier("bar"),
+		},
+		plan.NewFilter(
+			expression.NewEquals(
+				expression.NewIdentifier("foo"),
+				expression.NewIdentifier("bar"),
+			),
+			table,
+		),
+	), node)
+
+	node, err = parsePlan(sql.NewCatalog(db), strings.NewReader(`SELECT foo FROM foo`))
+	require.Nil(err)
+	require.Equal(plan.NewProject(
+		[]sql.Expression{expression.NewIdentifier("foo")},
+		table,
+	), node)
+
+	require.Nil(table.Insert("a", "a"))
+	require.Nil(table.Insert("a", "b"))
+	node, err = Parse(db, strings.NewReader(testSelect))
+	require.Nil(err)
+	iter, err := node.RowIter()
+	require.Nil(err)
+	row, err := iter.Next()
+	require.Nil(err)
+	require.Equal(sql.NewMemoryRow("a", "a"), row)
+	_, err = iter.Next()
+	require.Equal(io.EOF, err)
+
+	_, err = Parse(db, strings.NewReader(`SELECT foo FROM bar`))
+	require.NotNil(err)
+
+	_, err = Parse(db, strings.NewReader(`SELECT foo FROM foo,`))
+	require.NotNil(err)
+}
+
+func TestParseLexerErrors(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	db.AddTable("foo", mem.NewTable("foo", sql.Schema{
+		sql.Field{Name: "foo", Type: sql.String},
+	}))
+
+	errors := map[string]string{
+		`SELECT foo FROM foo WHERE foo != 'a'`: `unexpected character: '!'`,
+		`SELECT 'unterminated FROM foo`:        `unterminated string: 'unterminated FROM foo`,
+		`SELECT 1abc FROM foo`:                 `invalid number syntax: "1a"`,
+	}
+
+	for query, expected := range errors {
+		_, err := Parse(db, strings.NewReader(query))
+		require.EqualError(err, expected, query)
+	}
+}
+
+func TestParseOrderBy(t *testing.T) {
+	require := require.New(t)
+	cases := []struct {
+		input  string
+		fields []plan.SortField
+	}{
+		{
+			`SELECT foo FROM foo ORDER BY foo DESC, bar`,
+			[]plan.SortField{
+				{Expression: expression.NewIdentifier("foo"), Order: plan.Descending},
+				{Expression: expression.NewIdentifier("bar"), Order: plan.Ascending},
+			},
+		},
+		{
+			`SELECT foo FROM foo WHERE foo = bar ORDER BY bar ASC;`,
+			[]plan.SortField{
+				{Expression: expression.NewIdentifier("bar"), Order: plan.Ascending},
+			},
+		},
+		{
+			`SELECT foo FROM foo ORDER BY foo = bar desc`,
+			[]plan.SortField{
+				{
+					Expression: expression.NewEquals(
+						expression.NewIdentifier("foo"),
+						expression.NewIdentifier("bar"),
+					),
+					Order: plan.Descending,
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		p := newParser(strings.NewReader(c.input))
+		require.Nil(p.parse())
+		require.Nil(p.err, c.input)
+		require.Equal(c.fields, p.orderClauses, c.input)
+	}
+
+	p := newParser(strings.NewReader(`SELECT foo FROM foo ORDER BY foo bar`))
+	require.Nil(p.parse())
+	require.NotNil(p.err)
+}
+
+func TestParseLimitOffset(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	table := mem.NewTable("foo", sql.Schema{
+		sql.Field{Name: "foo", Type: sql.String},
+	})
+	db.AddTable("foo", table)
+
+	node, err := parsePlan(sql.NewCatalog(db), strings.NewReader(`SELECT foo FROM foo LIMIT 10`))
+	require.Nil(err)
+	require.Equal(plan.NewLimit(10, plan.NewProject(
+		[]sql.Expression{expression.NewIdentifier("foo")},
+		table,
+	)), node)
+
+	node, err = parsePlan(sql.NewCatalog(db), strings.NewReader(
+		`SELECT foo FROM foo ORDER BY foo DESC LIMIT 10 OFFSET 5;`,
+	))
+	require.Nil(err)
+	require.Equal(plan.NewLimit(10, plan.NewOffset(5, plan.NewProject(
+		[]sql.Expression{expression.NewIdentifier("foo")},
+		plan.NewSort([]plan.SortField{
+			{Expression: expression.NewIdentifier("foo"), Order: plan.Descending},
+		}, table),
+	))), node)
+
+	node, err = parsePlan(sql.NewCatalog(db), strings.NewReader(`SELECT foo FROM foo OFFSET 5`))
+	require.Nil(err)
+	require.Equal(plan.NewOffset(5, plan.NewProject(
+		[]sql.Expression{expression.NewIdentifier("foo")},
+		table,
+	)), node)
+
+	node, err = parsePlan(sql.NewCatalog(db), strings.NewReader(`SELECT foo FROM foo OFFSET 5 LIMIT 10`))
+	require.Nil(err)
+	require.Equal(plan.NewLimit(10, plan.NewOffset(5, plan.NewProject(
+		[]sql.Expression{expression.NewIdentifier("foo")},
+		table,
+	))), node)
+
+	errorCases := []string{
+		`SELECT foo FROM foo LIMIT`,
+		`SELECT foo FROM foo LIMIT foo`,
+		`SELECT foo FROM foo LIMIT 10 WHERE foo = 1`,
+	}
+	for _, c := range errorCases {
+		_, err := Parse(db, strings.NewReader(c))
+		require.NotNil(err, c)
+	}
+
+	errors := map[string]string{
+		`SELECT foo FROM foo LIMIT 1 OFFSET 2 LIMIT 3`:  `expecting end of sentence, "LIMIT" received`,
+		`SELECT foo FROM foo OFFSET 1 LIMIT 2 OFFSET 3`: `expecting end of sentence, "OFFSET" received`,
+		`SELECT foo FROM foo OFFSET 1 ORDER BY foo`:     `expecting "limit" or end of sentence, "ORDER" received`,
+	}
+	for query, expected := range errors {
+		_, err := Parse(db, strings.NewReader(query))
+		require.EqualError(err, expected, query)
+	}
+}
+
+func TestParseDistinct(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	table := mem.NewTable("foo", sql.Schema{
+		sql.Field{Name: "foo", Type: sql.String},
+	})
+	db.AddTable("foo", table)
+
+	node, err := parsePlan(sql.NewCatalog(db), strings.NewReader(`SELECT DISTINCT foo FROM foo LIMIT 5`))
+	require.Nil(err)
+	require.Equal(plan.NewLimit(5, plan.NewDistinct(plan.NewProject(
+		[]sql.Expression{expression.NewIdentifier("foo")},
+		table,
+	))), node)
+
+	_, err = Parse(db, strings.NewReader(`SELECT DISTINCT DISTINCT foo FROM foo`))
+	require.NotNil(err)
+
+	_, err = Parse(db, strings.NewReader(`SELECT DISTINCT FROM foo`))
+	require.NotNil(err)
+}
+
+func TestParseGroupBy(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	table := mem.NewTable("foo", sql.Schema{
+		sql.Field{Name: "foo", Type: sql.String},
+		sql.Field{Name: "bar", Type: sql.Integer},
+	})
+	db.AddTable("foo", table)
+
+	node, err := parsePlan(sql.NewCatalog(db), strings.NewReader(
+		`SELECT foo, COUNT(*), count(DISTINCT bar), SUM(bar) FROM foo
+		WHERE foo = 'a' GROUP BY foo ORDER BY count(*) DESC LIMIT 5`,
+	))
+	require.Nil(err)
+	require.Equal(plan.NewLimit(5, plan.NewSort(
+		[]plan.SortField{{
+			Expression: expression.NewCount(expression.NewStar()),
+			Order:      plan.Descending,
+		}},
+		plan.NewGroupBy(
+			[]sql.Expression{
+				expression.NewIdentifier("foo"),
+				expression.NewCount(expression.NewStar()),
+				expression.NewCountDistinct(expression.NewIdentifier("bar")),
+				expression.NewSum(expression.NewIdentifier("bar")),
+			},
+			[]sql.Expression{expression.NewIdentifier("foo")},
+			plan.NewFilter(
+				expression.NewEquals(
+					expression.NewIdentifier("foo"),
+					expression.NewLiteral("a", sql.String),
+				),
+				table,
+			),
+		),
+	)), node)
+
+	node, err = parsePlan(sql.NewCatalog(db), strings.NewReader(`SELECT min(bar), max(bar), avg(bar) FROM foo`))
+	require.Nil(err)
+	require.Equal(plan.NewGroupBy(
+		[]sql.Expression{
+			expression.NewMin(expression.NewIdentifier("bar")),
+			expression.NewMax(expression.NewIdentifier("bar")),
+			expression.NewAvg(expression.NewIdentifier("bar")),
+		},
+		nil,
+		table,
+	), node)
+
+	errorCases := []string{
+		`SELECT foo FROM foo GROUP foo`,
+		`SELECT foo FROM foo GROUP BY`,
+		`SELECT foo FROM foo ORDER BY foo GROUP BY foo`,
+		`SELECT sum(*) FROM foo`,
+		`SELECT sum(DISTINCT bar) FROM foo`,
+		`SELECT count(foo, bar) FROM foo`,
+		`SELECT nope(foo) FROM foo`,
+	}
+	for _, c := range errorCases {
+		_, err := Parse(db, strings.NewReader(c))
+		require.NotNil(err, c)
+	}
+}
+
+func TestParseHaving(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	table := mem.NewTable("foo", sql.Schema{
+		sql.Field{Name: "foo", Type: sql.String},
+		sql.Field{Name: "bar", Type: sql.Integer},
+	})
+	require.Nil(table.Insert("a", int32(1)))
+	require.Nil(table.Insert("b", int32(2)))
+	require.Nil(table.Insert("a", int32(3)))
+	require.Nil(table.Insert("c", int32(4)))
+	require.Nil(table.Insert("c", int32(5)))
+	db.AddTable("foo", table)
+
+	node, err := parsePlan(sql.NewCatalog(db), strings.NewReader(
+		`SELECT foo FROM foo GROUP BY foo HAVING count(*) = 2 ORDER BY max(bar) DESC`,
+	))
+	require.Nil(err)
+	require.Equal(plan.NewProject(
+		[]sql.Expression{expression.NewIdentifier("foo")},
+		plan.NewSort(
+			[]plan.SortField{{
+				Expression: expression.NewMax(expression.NewIdentifier("bar")),
+				Order:      plan.Descending,
+			}},
+			plan.NewFilter(
+				expression.NewEquals(
+					expression.NewCount(expression.NewStar()),
+					expression.NewLiteral(int64(2), sql.BigInteger),
+				),
+				plan.NewGroupBy(
+					[]sql.Expression{
+						expression.NewIdentifier("foo"),
+						expression.NewCount(expression.NewStar()),
+						expression.NewMax(expression.NewIdentifier("bar")),
+					},
+					[]sql.Expression{expression.NewIdentifier("foo")},
+					table,
+				),
+			),
+		),
+	), node)
+
+	node, err = analyzer.Analyze(node)
+	require.Nil(err)
+	require.Equal(sql.Schema{sql.Field{Name: "foo", Type: sql.String, Source: "foo"}}, node.Schema())
+
+	iter, err := node.RowIter()
+	require.Nil(err)
+	row, err := iter.Next()
+	require.Nil(err)
+	require.Equal(sql.NewMemoryRow("c"), row)
+	row, err = iter.Next()
+	require.Nil(err)
+	require.Equal(sql.NewMemoryRow("a"), row)
+	_, err = iter.Next()
+	require.Equal(io.EOF, err)
+
+	_, err = Parse(db, strings.NewReader(`SELECT foo FROM foo GROUP BY foo HAVING`))
+	require.NotNil(err)
+}
+
+func TestParseJoins(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	foo := mem.NewTable("foo", sql.Schema{
+		sql.Field{Name: "foo", Type: sql.String},
+	})
+	bar := mem.NewTable("bar", sql.Schema{
+		sql.Field{Name: "bar", Type: sql.String},
+	})
+	db.AddTable("foo", foo)
+	db.AddTable("bar", bar)
+
+	projection := []sql.Expression{expression.NewIdentifier("foo")}
+	cond := expression.NewEquals(
+		expression.NewIdentifier("foo"),
+		expression.NewIdentifier("bar"),
+	)
+
+	cases := []struct {
+		input    string
+		expected sql.Node
+	}{
+		{
+			`SELECT foo FROM foo, bar`,
+			plan.NewProject(projection, plan.NewCrossJoin(foo, bar)),
+		},
+		{
+			`SELECT foo FROM foo CROSS JOIN bar WHERE foo = bar`,
+			plan.NewProject(projection, plan.NewFilter(cond, plan.NewCrossJoin(foo, bar))),
+		},
+		{
+			`SELECT foo FROM foo JOIN bar ON foo = bar`,
+			plan.NewProject(projection, plan.NewInnerJoin(foo, bar, cond)),
+		},
+		{
+			`SELECT foo FROM foo INNER JOIN bar ON foo = bar ORDER BY foo`,
+			plan.NewProject(projection, plan.NewSort(
+				[]plan.SortField{
+					{Expression: expression.NewIdentifier("foo"), Order: plan.Ascending},
+				},
+				plan.NewInnerJoin(foo, bar, cond),
+			)),
+		},
+		{
+			`SELECT foo FROM foo LEFT JOIN bar ON foo = bar, foo`,
+			plan.NewProject(projection, plan.NewCrossJoin(
+				plan.NewLeftJoin(foo, bar, cond),
+				foo,
+			)),
+		},
+		{
+			`SELECT foo FROM foo LEFT OUTER JOIN bar ON foo = bar`,
+			plan.NewProject(projection, plan.NewLeftJoin(foo, bar, cond)),
+		},
+	}
+
+	for _, c := range cases {
+		node, err := parsePlan(sql.NewCatalog(db), strings.NewReader(c.input))
+		require.Nil(err, c.input)
+		require.Equal(c.expected, node, c.input)
+	}
+
+	errorCases := []string{
+		`SELECT foo FROM foo JOIN bar`,
+		`SELECT foo FROM foo JOIN bar WHERE foo = bar`,
+		`SELECT foo FROM foo INNER bar ON foo = bar`,
+		`SELECT foo FROM foo JOIN ON foo = bar`,
+		`SELECT foo FROM foo JOIN baz ON foo = bar`,
+	}
+	for _, c := range errorCases {
+		_, err := Parse(db, strings.NewReader(c))
+		require.NotNil(err, c)
+	}
+}
+
+func TestParseFunctionCall(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	table := mem.NewTable("foo", sql.Schema{
+		sql.Field{Name: "foo", Type: sql.String},
+	})
+	require.Nil(table.Insert("abc"))
+	db.AddTable("foo", table)
+
+	require.Nil(expression.DefaultRegistry.Register(expression.Func(
+		"test_concat", sql.String, []sql.Type{sql.String, sql.String},
+		func(args ...interface{}) (interface{}, error) {
+			return args[0].(string) + args[1].(string), nil
+		},
+	)))
+
+	node, err := Parse(db, strings.NewReader(
+		`SELECT test_concat(foo, test_concat('-', foo)) FROM foo`,
+	))
+	require.Nil(err)
+
+	iter, err := node.RowIter()
+	require.Nil(err)
+	row, err := iter.Next()
+	require.Nil(err)
+	require.Equal(sql.NewMemoryRow("abc-abc"), row)
+
+	_, err = Parse(db, strings.NewReader(`SELECT test_concat(foo) FROM foo`))
+	require.EqualError(err, `function "test_concat" expects 2 arguments, 1 received`)
+
+	_, err = Parse(db, strings.NewReader(`SELECT nope(foo) FROM foo`))
+	require.EqualError(err, `unknown function "nope"`)
+}
+
+func TestParseComparisons(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	table := mem.NewTable("foo", sql.Schema{
+		sql.Field{Name: "n", Type: sql.BigInteger},
+	})
+	for i := int64(1); i <= 5; i++ {
+		require.Nil(table.Insert(i))
+	}
+	db.AddTable("foo", table)
+
+	testCases := map[string][]sql.Row{
+		`SELECT n FROM foo WHERE n > 3`:  {sql.NewMemoryRow(int64(4)), sql.NewMemoryRow(int64(5))},
+		`SELECT n FROM foo WHERE n >= 4`: {sql.NewMemoryRow(int64(4)), sql.NewMemoryRow(int64(5))},
+		`SELECT n FROM foo WHERE n < 2`:  {sql.NewMemoryRow(int64(1))},
+		`SELECT n FROM foo WHERE 2 >= n`: {sql.NewMemoryRow(int64(1)), sql.NewMemoryRow(int64(2))},
+		`SELECT n FROM foo WHERE n <> 3 ORDER BY n DESC LIMIT 2`: {
+			sql.NewMemoryRow(int64(5)), sql.NewMemoryRow(int64(4)),
+		},
+	}
+
+	assertQueryRows(t, db, testCases)
+}
+
+func TestParseBooleanConnectives(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	table := mem.NewTable("foo", sql.Schema{
+		sql.Field{Name: "n", Type: sql.BigInteger},
+		sql.Field{Name: "s", Type: sql.String},
+	})
+	require.Nil(table.Insert(int64(1), "a"))
+	require.Nil(table.Insert(int64(2), "b"))
+	require.Nil(table.Insert(int64(3), "a"))
+	db.AddTable("foo", table)
+
+	assertQueryRows(t, db, map[string][]sql.Row{
+		`SELECT n FROM foo WHERE s = 'a' AND n > 1`: {sql.NewMemoryRow(int64(3))},
+		`SELECT n FROM foo WHERE n = 1 OR n = 2`: {
+			sql.NewMemoryRow(int64(1)), sql.NewMemoryRow(int64(2)),
+		},
+		`SELECT n FROM foo WHERE s = 'a' XOR n > 1`: {
+			sql.NewMemoryRow(int64(1)), sql.NewMemoryRow(int64(2)),
+		},
+		`SELECT n FROM foo WHERE n = 3 OR s = 'b' and n < 3`: {
+			sql.NewMemoryRow(int64(2)), sql.NewMemoryRow(int64(3)),
+		},
+	})
+
+	_, err := Parse(db, strings.NewReader(`SELECT n FROM foo WHERE n`))
+	require.EqualError(err, `filter condition "n" must be boolean, biginteger received`)
+
+	_, err = Parse(db, strings.NewReader(`SELECT s, count(*) FROM foo GROUP BY s HAVING s`))
+	require.EqualError(err, `filter condition "s" must be boolean, string received`)
+}
+
+func assertQueryRows(t *testing.T, db sql.Database, testCases map[string][]sql.Row) {
+	assertCatalogQueryRows(t, sql.NewCatalog(db), testCases)
+}
+
+func assertCatalogQueryRows(t *testing.T, catalog *sql.Catalog, testCases map[string][]sql.Row) {
+	require := require.New(t)
+	for query, expected := range testCases {
+		node, err := ParseCatalog(catalog, strings.NewReader(query))
+		require.Nil(err, query)
+
+		iter, err := node.RowIter()
+		require.Nil(err, query)
+
+		var rows []sql.Row
+		for {
+			row, err := iter.Next()
+			if err == io.EOF {
+				break
+			}
+			require.Nil(err, query)
+			rows = append(rows, row)
+		}
+		require.Equal(expected, rows, query)
+	}
+}
+
+func TestParseArithmetic(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	table := mem.NewTable("commits", sql.Schema{
+		sql.Field{Name: "author_time", Type: sql.Timestamp},
+		sql.Field{Name: "comitter_time", Type: sql.Timestamp},
+	})
+	require.Nil(table.Insert(int64(1000), int64(1600)))
+	require.Nil(table.Insert(int64(2000), int64(2010)))
+	db.AddTable("commits", table)
+
+	assertQueryRows(t, db, map[string][]sql.Row{
+		`SELECT author_time FROM commits WHERE comitter_time - author_time > 60`: {
+			sql.NewMemoryRow(int64(1000)),
+		},
+		`SELECT author_time FROM commits WHERE author_time = -2 * -500 + 10 % 3 - 1`: {
+			sql.NewMemoryRow(int64(1000)),
+		},
+		`SELECT author_time FROM commits WHERE (comitter_time - author_time) / 10 = 1`: {
+			sql.NewMemoryRow(int64(2000)),
+		},
+		`SELECT (comitter_time - author_time) / 20 AS minutes FROM commits WHERE (comitter_time - author_time) * 0.5 > 5.5`: {
+			sql.NewMemoryRow(float64(30)),
+		},
+		`SELECT avg(comitter_time - author_time) FROM commits`: {
+			sql.NewMemoryRow(float64(305)),
+		},
+	})
+
+	node, err := Parse(db, strings.NewReader(`SELECT author_time FROM commits WHERE 1 / 0 = 1`))
+	require.Nil(err)
+	iter, err := node.RowIter()
+	require.Nil(err)
+	_, err = iter.Next()
+	require.Equal(expression.ErrDivisionByZero, err)
+}
+
+func TestParseTimestamps(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	table := mem.NewTable("commits", sql.Schema{
+		sql.Field{Name: "hash", Type: sql.String},
+		sql.Field{Name: "author_time", Type: sql.Timestamp},
+		sql.Field{Name: "secs", Type: sql.Integer},
+	})
+	// 2022-12-31 23:00:00, 2023-01-02 10:00:00 and 2023-01-05 08:30:00 UTC
+	require.Nil(table.Insert("h1", int64(1672527600), int32(1672527600)))
+	require.Nil(table.Insert("h2", int64(1672653600), int32(0)))
+	require.Nil(table.Insert("h3", int64(1672907400), int32(1672907400)))
+	db.AddTable("commits", table)
+
+	assertQueryRows(t, db, map[string][]sql.Row{
+		`SELECT hash FROM commits WHERE author_time > '2023-01-01'`: {
+			sql.NewMemoryRow("h2"), sql.NewMemoryRow("h3"),
+		},
+		`SELECT hash FROM commits WHERE '2023-01-02 10:00:00' = author_time`: {
+			sql.NewMemoryRow("h2"),
+		},
+		`SELECT year(author_time), month(author_time), day_of_week(author_time) FROM commits WHERE hash = 'h1'`: {
+			sql.NewMemoryRow(int32(2022), int32(12), int32(6)),
+		},
+		`SELECT format_time(date_trunc('week', author_time), '%Y-%m-%d') AS week, count(*) FROM commits GROUP BY date_trunc('week', author_time)`: {
+			sql.NewMemoryRow("2022-12-26", int64(1)), sql.NewMemoryRow("2023-01-02", int64(2)),
+		},
+		`SELECT hash FROM commits WHERE author_time = from_unixtime(1672653600)`: {
+			sql.NewMemoryRow("h2"),
+		},
+		`SELECT hash FROM commits WHERE author_time = from_unixtime(secs)`: {
+			sql.NewMemoryRow("h1"), sql.NewMemoryRow("h3"),
+		},
+		`SELECT year('2020-01-01'), format_time('2020-01-01 10:30:00', '%H:%M') FROM commits WHERE hash = 'h1'`: {
+			sql.NewMemoryRow(int32(2020), "10:30"),
+		},
+	})
+
+	_, err := Parse(db, strings.NewReader(`SELECT from_unixtime(hash) FROM commits`))
+	require.EqualError(err, `argument 1 of function "from_unixtime" must be biginteger, string received`)
+}
+
+func TestParseTimeZones(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	table := mem.NewTable("commits", sql.Schema{
+		sql.Field{Name: "hash", Type: sql.String},
+		sql.Field{Name: "author_time", Type: sql.Timestamp},
+		sql.Field{Name: "author_tz", Type: sql.String},
+	})
+	// 2023-01-02 10:00:00 UTC, authored in Madrid and in San Francisco
+	require.Nil(table.Insert("h1", int64(1672653600), "+0100"))
+	require.Nil(table.Insert("h2", int64(1672653600), "-0800"))
+	db.AddTable("commits", table)
+
+	assertQueryRows(t, db, map[string][]sql.Row{
+		`SELECT hash, hour(author_time), hour(author_time, author_tz) FROM commits`: {
+			sql.NewMemoryRow("h1", int32(10), int32(11)),
+			sql.NewMemoryRow("h2", int32(10), int32(2)),
+		},
+		`SELECT hash FROM commits WHERE format_time(author_time, '%H:%M', author_tz) < '09:00'`: {
+			sql.NewMemoryRow("h2"),
+		},
+	})
+}
+
+func TestParseNull(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	people := mem.NewTable("people", sql.Schema{
+		sql.Field{Name: "name", Type: sql.String},
+		sql.Field{Name: "email", Type: sql.String, Nullable: true},
+	})
+	require.Nil(people.Insert("alice", "alice@example.com"))
+	require.Nil(people.Insert("bob", nil))
+	db.AddTable("people", people)
+
+	commits := mem.NewTable("commits", sql.Schema{
+		sql.Field{Name: "author_email", Type: sql.String},
+	})
+	require.Nil(commits.Insert("alice@example.com"))
+	db.AddTable("commits", commits)
+
+	assertQueryRows(t, db, map[string][]sql.Row{
+		`SELECT name FROM people WHERE email IS NULL`: {sql.NewMemoryRow("bob")},
+		`SELECT name FROM people WHERE email IS NOT NULL`: {
+			sql.NewMemoryRow("alice"),
+		},
+		`SELECT name FROM people WHERE NOT email = 'alice@example.com'`: nil,
+		`SELECT name FROM people WHERE email = NULL`:                    nil,
+		`SELECT name FROM people WHERE email = 'x' OR NOT name = 'alice'`: {
+			sql.NewMemoryRow("bob"),
+		},
+		`SELECT name FROM people LEFT JOIN commits ON email = author_email WHERE author_email IS NULL`: {
+			sql.NewMemoryRow("bob"),
+		},
+	})
+
+	_, err := Parse(db, strings.NewReader(`SELECT name FROM people WHERE email IS 1`))
+	require.EqualError(err, `expecting NULL after "IS"`)
+}
+
+func TestParseLikeRegexp(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	table := mem.NewTable("commits", sql.Schema{
+		sql.Field{Name: "message", Type: sql.String},
+	})
+	require.Nil(table.Insert("Revert \"add foo\""))
+	require.Nil(table.Insert("PROJ-12: add foo"))
+	require.Nil(table.Insert("100% done"))
+	db.AddTable("commits", table)
+
+	assertQueryRows(t, db, map[string][]sql.Row{
+		`SELECT message FROM commits WHERE message LIKE 'Revert%'`: {
+			sql.NewMemoryRow("Revert \"add foo\""),
+		},
+		`SELECT message FROM commits WHERE message NOT LIKE '%foo%'`: {
+			sql.NewMemoryRow("100% done"),
+		},
+		`SELECT message FROM commits WHERE message LIKE '%!%%' ESCAPE '!' AND message LIKE '1%'`: {
+			sql.NewMemoryRow("100% done"),
+		},
+		`SELECT message FROM commits WHERE message REGEXP '^[A-Z]+-[0-9]+'`: {
+			sql.NewMemoryRow("PROJ-12: add foo"),
+		},
+		`SELECT message FROM commits WHERE message NOT REGEXP 'foo' OR message REGEXP '^Rev'`: {
+			sql.NewMemoryRow("Revert \"add foo\""), sql.NewMemoryRow("100% done"),
+		},
+	})
+
+	_, err := Parse(db, strings.NewReader(`SELECT message FROM commits WHERE message NOT 'foo'`))
+	require.EqualError(err, `expecting LIKE or REGEXP after "NOT"`)
+}
+
+func TestParseCase(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	table := mem.NewTable("commits", sql.Schema{
+		sql.Field{Name: "message", Type: sql.String},
+		sql.Field{Name: "files", Type: sql.Integer},
+	})
+	require.Nil(table.Insert("Merge branch 'foo'", int32(0)))
+	require.Nil(table.Insert("Revert \"add foo\"", int32(1)))
+	require.Nil(table.Insert("add foo", int32(2)))
+	require.Nil(table.Insert("add bar", int32(3)))
+	db.AddTable("commits", table)
+
+	assertQueryRows(t, db, map[string][]sql.Row{
+		`SELECT CASE WHEN message LIKE 'Merge%' THEN 'merge' WHEN message LIKE 'Revert%' THEN 'revert' ELSE 'feature' END AS kind FROM commits`: {
+			sql.NewMemoryRow("merge"), sql.NewMemoryRow("revert"),
+			sql.NewMemoryRow("feature"), sql.NewMemoryRow("feature"),
+		},
+		`SELECT CASE files WHEN 0 THEN 'none' WHEN 1 THEN 'one' END FROM commits WHERE files < 3`: {
+			sql.NewMemoryRow("none"), sql.NewMemoryRow("one"), sql.NewMemoryRow(nil),
+		},
+		`SELECT 1 + CASE WHEN files > 1 THEN files * 10 ELSE 0 END * 2 AS n FROM commits WHERE files > 1`: {
+			sql.NewMemoryRow(int64(41)), sql.NewMemoryRow(int64(61)),
+		},
+		`SELECT message FROM commits WHERE CASE WHEN (files > 2) THEN true END`: {
+			sql.NewMemoryRow("add bar"),
+		},
+	})
+
+	errors := map[string]string{
+		`SELECT CASE WHEN files > 1 THEN 'many' FROM commits`:            `expecting END of CASE`,
+		`SELECT CASE WHEN files > 1 'many' END FROM commits`:             `malformed CASE expression`,
+		`SELECT CASE WHEN files > 1 ELSE 'many' END FROM commits`:        `expecting THEN after WHEN`,
+		`SELECT CASE ELSE 'many' END FROM commits`:                       `expecting WHEN in CASE`,
+		`SELECT CASE WHEN THEN 'many' END FROM commits`:                  `expecting expression before WHEN`,
+		`SELECT CASE WHEN files > 1 THEN 'many' ELSE 1 END FROM commits`: `values of CASE can't be both string and biginteger`,
+	}
+
+	for query, expected := range errors {
+		_, err := Parse(db, strings.NewReader(query))
+		require.EqualError(err, expected, query)
+	}
+}
+
+func TestParseCast(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	table := mem.NewTable("foo", sql.Schema{
+		sql.Field{Name: "s", Type: sql.String},
+	})
+	require.Nil(table.Insert("1"))
+	require.Nil(table.Insert("20"))
+	require.Nil(table.Insert("3000000000"))
+	db.AddTable("foo", table)
+
+	assertQueryRows(t, db, map[string][]sql.Row{
+		`SELECT CAST(s AS BIGINTEGER) AS n FROM foo WHERE CAST(s AS biginteger) > 2`: {
+			sql.NewMemoryRow(int64(20)), sql.NewMemoryRow(int64(3000000000)),
+		},
+		`SELECT s FROM foo WHERE CAST(s AS biginteger) < CAST('3' AS biginteger) + 1`: {
+			sql.NewMemoryRow("1"),
+		},
+		`SELECT CAST(1.9 AS integer), CAST(CAST(s AS float) / 8 AS biginteger) FROM foo WHERE s = '20'`: {
+			sql.NewMemoryRow(int32(1), int64(2)),
+		},
+	})
+
+	node, err := Parse(db, strings.NewReader(`SELECT CAST(s AS integer) FROM foo`))
+	require.Nil(err)
+	iter, err := node.RowIter()
+	require.Nil(err)
+	for {
+		_, err = iter.Next()
+		if err != nil {
+			break
+		}
+	}
+	require.EqualError(err, "value 3000000000 overflows int32")
+
+	errors := map[string]string{
+		`SELECT CAST(s) FROM foo`:            `expecting CAST(expression AS type)`,
+		`SELECT CAST(s AS decimal) FROM foo`: `unknown type "decimal"`,
+	}
+
+	for query, expected := range errors {
+		_, err := Parse(db, strings.NewReader(query))
+		require.EqualError(err, expected, query)
+	}
+}
+
+func TestParseAliases(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	table := mem.NewTable("commits", sql.Schema{
+		sql.Field{Name: "author", Type: sql.String},
+		sql.Field{Name: "author_time", Type: sql.Timestamp},
+		sql.Field{Name: "comitter_time", Type: sql.Timestamp},
+	})
+	require.Nil(table.Insert("alice", int64(10), int64(30)))
+	require.Nil(table.Insert("bob", int64(10), int64(20)))
+	require.Nil(table.Insert("alice", int64(20), int64(50)))
+	db.AddTable("commits", table)
+
+	assertQueryRows(t, db, map[string][]sql.Row{
+		`SELECT author, comitter_time - author_time AS latency FROM commits AS c ORDER BY latency DESC`: {
+			sql.NewMemoryRow("alice", int64(30)),
+			sql.NewMemoryRow("alice", int64(20)),
+			sql.NewMemoryRow("bob", int64(10)),
+		},
+		`SELECT author AS a, count(*) AS n FROM commits c GROUP BY author HAVING n > 1`: {
+			sql.NewMemoryRow("alice", int64(2)),
+		},
+	})
+
+	node, err := Parse(db, strings.NewReader(
+		`SELECT count(*) AS n, author_time + 1 AS t FROM commits c GROUP BY author_time`,
+	))
+	require.Nil(err)
+	require.Equal(sql.Schema{
+		sql.Field{Name: "n", Type: sql.BigInteger},
+		sql.Field{Name: "t", Type: sql.Timestamp},
+	}, node.Schema())
+
+	_, err = Parse(db, strings.NewReader(`SELECT author AS 1 FROM commits`))
+	require.EqualError(err, `expecting alias name after "AS"`)
+
+	_, err = Parse(db, strings.NewReader(`SELECT author FROM commits AS`))
+	require.EqualError(err, `expecting alias name after "AS"`)
+}
+
+func TestParseQualifiedNames(t *testing.T) {
+	require := require.New(t)
+	db := mem.NewDatabase("test")
+	people := mem.NewTable("people", sql.Schema{
+		sql.Field{Name: "name", Type: sql.String},
+		sql.Field{Name: "email", Type: sql.String},
+	})
+	require.Nil(people.Insert("alice", "alice@example.com"))
+	require.Nil(people.Insert("bob", "bob@example.com"))
+	db.AddTable("people", people)
+
+	other := mem.NewDatabase("other")
+	commits := mem.NewTable("commits", sql.Schema{
+		sql.Field{Name: "hash", Type: sql.String},
+		sql.Field{Name: "email", Type: sql.String},
+	})
+	require.Nil(commits.Insert("h1", "bob@example.com"))
+	require.Nil(commits.Insert("h2", "alice@example.com"))
+	require.Nil(commits.Insert("h3", "bob@example.com"))
+	other.AddTable("commits", commits)
+
+	catalog := sql.NewCatalog(db, other)
+	testCases := map[string][]sql.Row{
+		`SELECT people.name, hash FROM people JOIN other.commits ON people.email = commits.email WHERE commits.hash <> 'h3'`: {
+			sql.NewMemoryRow("alice", "h2"), sql.NewMemoryRow("bob", "h1"),
+		},
+		`SELECT p.name, count(*) AS n FROM people p JOIN other.commits AS c ON p.email = c.email GROUP BY p.name ORDER BY p.name DESC`: {
+			sql.NewMemoryRow("bob", int64(2)), sql.NewMemoryRow("alice", int64(1)),
+		},
+		`SELECT a.name FROM people a, people b WHERE a.name = 'alice' AND b.name = 'bob'`: {
+			sql.NewMemoryRow("alice"),
+		},
+		`SELECT * FROM people WHERE name = 'bob'`: {
+			sql.NewMemoryRow("bob", "bob@example.com"),
+		},
+		`SELECT c.*, p.name FROM people p JOIN other.commits c ON p.email = c.email WHERE c.hash = 'h2'`: {
+			sql.NewMemoryRow("h2", "alice@example.com", "alice"),
+		},
+		`SELECT * FROM people ORDER BY name DESC`: {
+			sql.NewMemoryRow("bob", "bob@example.com"),
+			sql.NewMemoryRow("alice", "alice@example.com"),
+		},
+	}
+
+	assertCatalogQueryRows(t, catalog, testCases)
+
+	errors := map[string]string{
+		`SELECT email FROM people JOIN other.commits ON people.email = commits.email`: `ambiguous column "email", it could belong to any of the relations people, commits`,
+		`SELECT p.hash FROM people p`:           `unknown column "p.hash"`,
+		`SELECT people.name FROM people p`:      `unknown column "people.name"`,
+		`SELECT a.name FROM people a, people a`: `ambiguous column "a.name"`,
+		`SELECT name FROM nope.people`:          `database "nope" not found`,
+		`SELECT name FROM other.people`:         `relation "people" not found in database "other"`,
+		`SELECT people. FROM people`:            `expecting column name after "people."`,
+		`SELECT c.* FROM people p`:              `unknown relation "c" in "c.*"`,
+	}
+
+	for query, expected := range errors {
+		_, err := ParseCatalog(catalog, strings.NewReader(query))
+		require.EqualError(err, expected, query)
+	}
+}
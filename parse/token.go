@@ -23,6 +23,7 @@ const (
 	StringToken
 	OpToken
 	FunctionToken
+	StarToken
 )
 
 func NewToken(typ TokenType, value string, line, pos uint) *Token {
@@ -4,9 +4,9 @@ package parse
 
 import "fmt"
 
-const _TokenType_name = "ErrorTokenEOFTokenLeftParenTokenRightParenTokenCommaTokenDotTokenKeywordTokenIdentifierTokenIntTokenFloatTokenStringTokenOpToken"
+const _TokenType_name = "ErrorTokenEOFTokenLeftParenTokenRightParenTokenCommaTokenDotTokenKeywordTokenIdentifierTokenIntTokenFloatTokenStringTokenOpTokenFunctionTokenStarToken"
 
-var _TokenType_index = [...]uint8{0, 10, 18, 32, 47, 57, 65, 77, 92, 100, 110, 121, 128}
+var _TokenType_index = [...]uint8{0, 10, 18, 32, 47, 57, 65, 77, 92, 100, 110, 121, 128, 141, 150}
 
 func (i TokenType) String() string {
 	if i >= TokenType(len(_TokenType_index)-1) {
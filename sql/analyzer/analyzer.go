@@ -0,0 +1,26 @@
+package analyzer
+
+import "github.com/mvader/gitql/sql"
+
+// rule is a single transformation of the analyzer over a plan tree.
+type rule func(sql.Node) (sql.Node, error)
+
+var rules = []rule{
+	expandStars,
+	resolveColumns,
+	checkTypes,
+}
+
+// Analyze runs every analyzer rule over the given plan, returning a plan
+// that is ready to be executed.
+func Analyze(n sql.Node) (sql.Node, error) {
+	var err error
+	for _, r := range rules {
+		n, err = r(n)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return n, nil
+}
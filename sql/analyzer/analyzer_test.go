@@ -0,0 +1,218 @@
+package analyzer
+
+import (
+	"io"
+	"testing"
+
+	"github.com/mvader/gitql/mem"
+	"github.com/mvader/gitql/sql"
+	"github.com/mvader/gitql/sql/expression"
+	"github.com/mvader/gitql/sql/plan"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	require := require.New(t)
+	table := mem.NewTable("test", sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+		sql.Field{Name: "col2", Type: sql.Integer},
+	})
+	require.Nil(table.Insert("foo", int32(1)))
+	require.Nil(table.Insert("bar", int32(2)))
+
+	node, err := Analyze(plan.NewProject(
+		[]sql.Expression{expression.NewIdentifier("col1")},
+		plan.NewFilter(
+			expression.NewEquals(
+				expression.NewIdentifier("col2"),
+				expression.NewLiteral(int32(2), sql.Integer),
+			),
+			table,
+		),
+	))
+	require.Nil(err)
+	require.Equal(plan.NewProject(
+		[]sql.Expression{expression.NewGetFieldWithTable(0, "test", sql.String, "col1")},
+		plan.NewFilter(
+			expression.NewEquals(
+				expression.NewGetFieldWithTable(1, "test", sql.Integer, "col2"),
+				expression.NewLiteral(int32(2), sql.Integer),
+			),
+			table,
+		),
+	), node)
+
+	iter, err := node.RowIter()
+	require.Nil(err)
+	row, err := iter.Next()
+	require.Nil(err)
+	require.Equal(sql.NewMemoryRow("bar"), row)
+	_, err = iter.Next()
+	require.Equal(io.EOF, err)
+}
+
+func TestAnalyze_UnknownColumn(t *testing.T) {
+	require := require.New(t)
+	table := mem.NewTable("test", sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+	})
+
+	_, err := Analyze(plan.NewProject(
+		[]sql.Expression{expression.NewIdentifier("col2")},
+		table,
+	))
+	require.EqualError(err, `unknown column "col2"`)
+}
+
+func TestResolveIdentifier_Ambiguous(t *testing.T) {
+	require := require.New(t)
+	schema := sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+		sql.Field{Name: "col1", Type: sql.Integer},
+	}
+
+	_, err := resolveIdentifier("col1", schema)
+	require.EqualError(err, `ambiguous column "col1"`)
+}
+
+func TestAnalyze_GroupBy(t *testing.T) {
+	require := require.New(t)
+	table := mem.NewTable("test", sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+		sql.Field{Name: "col2", Type: sql.Integer},
+	})
+	require.Nil(table.Insert("foo", int32(1)))
+	require.Nil(table.Insert("bar", int32(2)))
+	require.Nil(table.Insert("foo", int32(3)))
+
+	node, err := Analyze(plan.NewSort(
+		[]plan.SortField{{
+			Expression: expression.NewCount(expression.NewIdentifier("col2")),
+			Order:      plan.Descending,
+		}},
+		plan.NewGroupBy(
+			[]sql.Expression{
+				expression.NewIdentifier("col1"),
+				expression.NewCount(expression.NewIdentifier("col2")),
+			},
+			[]sql.Expression{expression.NewIdentifier("col1")},
+			table,
+		),
+	))
+	require.Nil(err)
+
+	iter, err := node.RowIter()
+	require.Nil(err)
+	row, err := iter.Next()
+	require.Nil(err)
+	require.Equal(sql.NewMemoryRow("foo", int64(2)), row)
+	row, err = iter.Next()
+	require.Nil(err)
+	require.Equal(sql.NewMemoryRow("bar", int64(1)), row)
+
+	_, err = Analyze(plan.NewSort(
+		[]plan.SortField{{
+			Expression: expression.NewMax(expression.NewIdentifier("col2")),
+			Order:      plan.Descending,
+		}},
+		plan.NewGroupBy(
+			[]sql.Expression{expression.NewIdentifier("col1")},
+			[]sql.Expression{expression.NewIdentifier("col1")},
+			table,
+		),
+	))
+	require.EqualError(err, `aggregation "max(col2)" must appear in the select list`)
+}
+
+func TestAnalyze_Join(t *testing.T) {
+	require := require.New(t)
+	left := mem.NewTable("left", sql.Schema{
+		sql.Field{Name: "name", Type: sql.String},
+		sql.Field{Name: "email", Type: sql.String},
+	})
+	right := mem.NewTable("right", sql.Schema{
+		sql.Field{Name: "author_email", Type: sql.String},
+		sql.Field{Name: "name", Type: sql.String},
+	})
+
+	node, err := Analyze(plan.NewInnerJoin(left, right, expression.NewEquals(
+		expression.NewIdentifier("email"),
+		expression.NewIdentifier("author_email"),
+	)))
+	require.Nil(err)
+	require.Equal(plan.NewInnerJoin(left, right, expression.NewEquals(
+		expression.NewGetFieldWithTable(1, "left", sql.String, "email"),
+		expression.NewGetFieldWithTable(2, "right", sql.String, "author_email"),
+	)), node)
+
+	_, err = Analyze(plan.NewProject(
+		[]sql.Expression{expression.NewIdentifier("name")},
+		plan.NewCrossJoin(left, right),
+	))
+	require.EqualError(err, `ambiguous column "name", it could belong to any of the relations left, right`)
+}
+
+func TestAnalyze_CheckTypes(t *testing.T) {
+	require := require.New(t)
+	table := mem.NewTable("test", sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+		sql.Field{Name: "col2", Type: sql.Integer},
+	})
+
+	length := expression.Func("length", sql.BigInteger, []sql.Type{sql.String},
+		func(args ...interface{}) (interface{}, error) {
+			return int64(len(args[0].(string))), nil
+		},
+	)
+
+	call, err := length.New(expression.NewIdentifier("col1"))
+	require.Nil(err)
+	_, err = Analyze(plan.NewProject([]sql.Expression{call}, table))
+	require.Nil(err)
+
+	call, err = length.New(expression.NewIdentifier("col2"))
+	require.Nil(err)
+	_, err = Analyze(plan.NewProject([]sql.Expression{call}, table))
+	require.EqualError(err, `argument 1 of function "length" must be string, integer received`)
+
+	_, err = Analyze(plan.NewFilter(expression.NewIdentifier("col2"), table))
+	require.EqualError(err, `filter condition "col2" must be boolean, integer received`)
+
+	_, err = Analyze(plan.NewInnerJoin(table, mem.NewTable("other", sql.Schema{
+		sql.Field{Name: "col3", Type: sql.String},
+	}), expression.NewIdentifier("col3")))
+	require.EqualError(err, `join condition "col3" must be boolean, string received`)
+
+	_, err = Analyze(plan.NewFilter(expression.NewLiteral(nil, sql.Null), table))
+	require.Nil(err)
+}
+
+func TestAnalyze_Stars(t *testing.T) {
+	require := require.New(t)
+	left := mem.NewTable("a", sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+	})
+	right := mem.NewTable("b", sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+		sql.Field{Name: "col2", Type: sql.Integer},
+	})
+	child := plan.NewCrossJoin(left, right)
+
+	node, err := Analyze(plan.NewProject([]sql.Expression{
+		expression.NewQualifiedStar("b"),
+		expression.NewStar(),
+	}, child))
+	require.Nil(err)
+	require.Equal(plan.NewProject([]sql.Expression{
+		expression.NewGetFieldWithTable(1, "b", sql.String, "col1"),
+		expression.NewGetFieldWithTable(2, "b", sql.Integer, "col2"),
+		expression.NewGetFieldWithTable(0, "a", sql.String, "col1"),
+		expression.NewGetFieldWithTable(1, "b", sql.String, "col1"),
+		expression.NewGetFieldWithTable(2, "b", sql.Integer, "col2"),
+	}, child), node)
+
+	_, err = Analyze(plan.NewProject([]sql.Expression{
+		expression.NewQualifiedStar("c"),
+	}, child))
+	require.EqualError(err, `unknown relation "c" in "c.*"`)
+}
@@ -0,0 +1,34 @@
+package analyzer
+
+import "github.com/mvader/gitql/sql"
+
+// typeChecker is implemented by expressions and nodes that can only check
+// the types of their children once they are resolved.
+type typeChecker interface {
+	CheckTypes() error
+}
+
+// checkTypes returns an error if any of the expressions or nodes of the plan
+// has children of types it does not accept.
+func checkTypes(n sql.Node) (sql.Node, error) {
+	return n.TransformUp(func(n sql.Node) (sql.Node, error) {
+		n, err := n.TransformExpressions(func(e sql.Expression) (sql.Expression, error) {
+			if c, ok := e.(typeChecker); ok {
+				if err := c.CheckTypes(); err != nil {
+					return nil, err
+				}
+			}
+			return e, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if c, ok := n.(typeChecker); ok {
+			if err := c.CheckTypes(); err != nil {
+				return nil, err
+			}
+		}
+		return n, nil
+	})
+}
@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/mvader/gitql/sql"
+	"github.com/mvader/gitql/sql/expression"
+	"github.com/mvader/gitql/sql/plan"
+)
+
+// expandStars replaces the stars in the expressions of every projection with
+// a field for each column of the schema of its child, or only for those of
+// the relation the star is qualified with.
+func expandStars(n sql.Node) (sql.Node, error) {
+	return n.TransformUp(func(n sql.Node) (sql.Node, error) {
+		p, ok := n.(*plan.Project)
+		if !ok {
+			return n, nil
+		}
+
+		child := p.Children()[0]
+		schema := child.Schema()
+		var exprs []sql.Expression
+		for _, e := range p.Expressions() {
+			s, ok := e.(*expression.Star)
+			if !ok {
+				exprs = append(exprs, e)
+				continue
+			}
+
+			fields, err := starFields(s, schema)
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, fields...)
+		}
+
+		return plan.NewProject(exprs, child), nil
+	})
+}
+
+func starFields(s *expression.Star, schema sql.Schema) ([]sql.Expression, error) {
+	var fields []sql.Expression
+	for i, f := range schema {
+		if s.Table() == "" || f.Source == s.Table() {
+			fields = append(fields, expression.NewGetFieldWithTable(i, f.Source, f.Type, f.Name))
+		}
+	}
+
+	if len(fields) == 0 && s.Table() != "" {
+		return nil, fmt.Errorf("unknown relation %q in %q", s.Table(), s.Name())
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mvader/gitql/sql"
+	"github.com/mvader/gitql/sql/expression"
+	"github.com/mvader/gitql/sql/plan"
+)
+
+// resolveColumns replaces every identifier in the expressions of a node with
+// the field of its children's schema it refers to. Aggregations outside of a
+// GroupBy refer to the column of the same name computed by the GroupBy below.
+func resolveColumns(n sql.Node) (sql.Node, error) {
+	return n.TransformUp(func(n sql.Node) (sql.Node, error) {
+		schema := childrenSchema(n)
+		if _, ok := n.(*plan.GroupBy); !ok {
+			var err error
+			n, err = n.TransformExpressions(func(e sql.Expression) (sql.Expression, error) {
+				if _, ok := e.(sql.Aggregation); !ok {
+					return e, nil
+				}
+
+				return resolveAggregation(e, schema)
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return n.TransformExpressions(func(e sql.Expression) (sql.Expression, error) {
+			i, ok := e.(*expression.Identifier)
+			if !ok {
+				return e, nil
+			}
+
+			return resolveQualifiedIdentifier(i.Table(), i.Name(), schema)
+		})
+	})
+}
+
+func resolveIdentifier(name string, schema sql.Schema) (sql.Expression, error) {
+	return resolveQualifiedIdentifier("", name, schema)
+}
+
+// resolveQualifiedIdentifier returns the field of the schema with the given
+// name whose source is the given table. Fields of any source are considered
+// if table is empty.
+func resolveQualifiedIdentifier(table, name string, schema sql.Schema) (sql.Expression, error) {
+	var matches []int
+	for i, f := range schema {
+		if f.Name == name && (table == "" || f.Source == table) {
+			matches = append(matches, i)
+		}
+	}
+
+	ident := expression.NewQualifiedIdentifier(table, name)
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("unknown column %q", ident.String())
+	case 1:
+		f := schema[matches[0]]
+		return expression.NewGetFieldWithTable(matches[0], f.Source, f.Type, f.Name), nil
+	}
+
+	var sources []string
+	for _, idx := range matches {
+		if s := schema[idx].Source; s != "" && !containsString(sources, s) {
+			sources = append(sources, s)
+		}
+	}
+
+	if table != "" || len(sources) < 2 {
+		return nil, fmt.Errorf("ambiguous column %q", ident.String())
+	}
+
+	return nil, fmt.Errorf(
+		"ambiguous column %q, it could belong to any of the relations %s",
+		ident.String(), strings.Join(sources, ", "),
+	)
+}
+
+func containsString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveAggregation(e sql.Expression, schema sql.Schema) (sql.Expression, error) {
+	for i, f := range schema {
+		if f.Name == e.Name() {
+			return expression.NewGetField(i, f.Type, f.Name), nil
+		}
+	}
+
+	return nil, fmt.Errorf("aggregation %q must appear in the select list", e.Name())
+}
+
+func childrenSchema(n sql.Node) sql.Schema {
+	var schema sql.Schema
+	for _, c := range n.Children() {
+		schema = append(schema, c.Schema()...)
+	}
+	return schema
+}
@@ -10,8 +10,18 @@ type Node interface {
 	Schema() Schema
 	Children() []Node
 	RowIter() (RowIter, error)
+	// TransformUp applies the given function to the children of the node,
+	// bottom-up, and then to the node rebuilt with the transformed children.
+	TransformUp(TransformNodeFunc) (Node, error)
+	// TransformExpressions applies the given function bottom-up to every
+	// expression of the node, without descending into its children.
+	TransformExpressions(TransformExprFunc) (Node, error)
 }
 
+// TransformNodeFunc is a function that returns a replacement for the given
+// node.
+type TransformNodeFunc func(Node) (Node, error)
+
 type PhysicalRelation interface {
 	Nameable
 	Node
@@ -1,6 +1,43 @@
 package sql
 
+import "fmt"
+
 type Database interface {
 	Nameable
 	Relations() map[string]PhysicalRelation
 }
+
+// Catalog holds the databases that can be queried. Relations that are not
+// qualified with the name of a database belong to the current one, which is
+// the first database added to the catalog.
+type Catalog struct {
+	databases []Database
+}
+
+func NewCatalog(dbs ...Database) *Catalog {
+	return &Catalog{databases: dbs}
+}
+
+// AddDatabase adds a database to the catalog.
+func (c *Catalog) AddDatabase(db Database) {
+	c.databases = append(c.databases, db)
+}
+
+// CurrentDatabase returns the database of the unqualified relations, or an
+// error if the catalog is empty.
+func (c *Catalog) CurrentDatabase() (Database, error) {
+	if len(c.databases) == 0 {
+		return nil, fmt.Errorf("no database selected")
+	}
+	return c.databases[0], nil
+}
+
+// Database returns the database with the given name.
+func (c *Catalog) Database(name string) (Database, error) {
+	for _, db := range c.databases {
+		if db.Name() == name {
+			return db, nil
+		}
+	}
+	return nil, fmt.Errorf("database %q not found", name)
+}
@@ -3,5 +3,27 @@ package sql
 type Expression interface {
 	Type() Type
 	Name() string
-	Eval(Row) interface{}
+	Eval(Row) (interface{}, error)
+	TransformUp(TransformExprFunc) (Expression, error)
+}
+
+// TransformExprFunc is a function that returns a replacement for the given
+// expression.
+type TransformExprFunc func(Expression) (Expression, error)
+
+// Aggregation is an expression that computes a single value out of all the
+// rows of a group.
+type Aggregation interface {
+	Expression
+	// NewBuffer returns an empty buffer to accumulate the rows of a group.
+	NewBuffer() AggregationBuffer
+}
+
+// AggregationBuffer holds the partial state of an aggregation over the rows
+// of a group.
+type AggregationBuffer interface {
+	// Update adds the given row to the buffer.
+	Update(Row) error
+	// Eval returns the result of the aggregation over the rows added so far.
+	Eval() (interface{}, error)
 }
@@ -0,0 +1,328 @@
+package expression
+
+import (
+	"fmt"
+
+	"github.com/mvader/gitql/sql"
+)
+
+func evalAggregation(name string) error {
+	return fmt.Errorf("aggregation %s can only be evaluated over a group", name)
+}
+
+// Count counts the rows of a group. If its child is a Star every row is
+// counted, otherwise only the rows in which the child is not nil.
+type Count struct {
+	child    sql.Expression
+	distinct bool
+}
+
+func NewCount(child sql.Expression) *Count {
+	return &Count{child: child}
+}
+
+// NewCountDistinct returns a Count that only counts distinct values of its
+// child.
+func NewCountDistinct(child sql.Expression) *Count {
+	return &Count{child: child, distinct: true}
+}
+
+func (c Count) Type() sql.Type {
+	return sql.BigInteger
+}
+
+func (c Count) Name() string {
+	if c.distinct {
+		return "count(distinct " + c.child.Name() + ")"
+	}
+	return "count(" + c.child.Name() + ")"
+}
+
+func (c Count) Eval(row sql.Row) (interface{}, error) {
+	return nil, evalAggregation(c.Name())
+}
+
+func (c Count) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	child, err := c.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(&Count{child: child, distinct: c.distinct})
+}
+
+func (c Count) NewBuffer() sql.AggregationBuffer {
+	return &countBuffer{c: c, seen: map[interface{}]bool{}}
+}
+
+type countBuffer struct {
+	c     Count
+	count int64
+	seen  map[interface{}]bool
+}
+
+func (b *countBuffer) Update(row sql.Row) error {
+	if _, ok := b.c.child.(*Star); ok {
+		b.count++
+		return nil
+	}
+
+	v, err := b.c.child.Eval(row)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	if b.c.distinct {
+		if b.seen[v] {
+			return nil
+		}
+		b.seen[v] = true
+	}
+
+	b.count++
+	return nil
+}
+
+func (b *countBuffer) Eval() (interface{}, error) {
+	return b.count, nil
+}
+
+// Sum adds up the values of its child over the rows of a group.
+type Sum struct {
+	child sql.Expression
+}
+
+func NewSum(child sql.Expression) *Sum {
+	return &Sum{child: child}
+}
+
+func (s Sum) Type() sql.Type {
+	if s.child.Type() == sql.Float {
+		return sql.Float
+	}
+	return sql.BigInteger
+}
+
+func (s Sum) Name() string {
+	return "sum(" + s.child.Name() + ")"
+}
+
+func (s Sum) Eval(row sql.Row) (interface{}, error) {
+	return nil, evalAggregation(s.Name())
+}
+
+func (s Sum) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	child, err := s.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewSum(child))
+}
+
+func (s Sum) NewBuffer() sql.AggregationBuffer {
+	return &sumBuffer{child: s.child}
+}
+
+type sumBuffer struct {
+	child sql.Expression
+	sum   int64
+	fsum  float64
+	count int64
+}
+
+func (b *sumBuffer) Update(row sql.Row) error {
+	v, err := b.child.Eval(row)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	switch n := v.(type) {
+	case int32:
+		b.sum += int64(n)
+	case int64:
+		b.sum += n
+	case float64:
+		b.fsum += n
+	default:
+		return fmt.Errorf("value %v of type %T can't be aggregated", v, v)
+	}
+
+	b.count++
+	return nil
+}
+
+func (b *sumBuffer) Eval() (interface{}, error) {
+	if b.count == 0 {
+		return nil, nil
+	}
+
+	if b.child.Type() == sql.Float {
+		return b.total(), nil
+	}
+	return b.sum, nil
+}
+
+// total returns the sum of both the integer and the float values.
+func (b *sumBuffer) total() float64 {
+	return float64(b.sum) + b.fsum
+}
+
+// Avg computes the average of the values of its child over the rows of a
+// group. The average of timestamps is a timestamp, and any other average is
+// a float.
+type Avg struct {
+	child sql.Expression
+}
+
+func NewAvg(child sql.Expression) *Avg {
+	return &Avg{child: child}
+}
+
+func (a Avg) Type() sql.Type {
+	if a.child.Type() == sql.Timestamp {
+		return sql.Timestamp
+	}
+	return sql.Float
+}
+
+func (a Avg) Name() string {
+	return "avg(" + a.child.Name() + ")"
+}
+
+func (a Avg) Eval(row sql.Row) (interface{}, error) {
+	return nil, evalAggregation(a.Name())
+}
+
+func (a Avg) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	child, err := a.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewAvg(child))
+}
+
+func (a Avg) NewBuffer() sql.AggregationBuffer {
+	return &avgBuffer{sumBuffer{child: a.child}}
+}
+
+type avgBuffer struct {
+	sumBuffer
+}
+
+func (b *avgBuffer) Eval() (interface{}, error) {
+	if b.count == 0 {
+		return nil, nil
+	}
+
+	if b.child.Type() == sql.Timestamp {
+		return b.sum / b.count, nil
+	}
+	return b.total() / float64(b.count), nil
+}
+
+// Min returns the smallest value of its child over the rows of a group.
+type Min struct {
+	child sql.Expression
+}
+
+func NewMin(child sql.Expression) *Min {
+	return &Min{child: child}
+}
+
+func (m Min) Type() sql.Type {
+	return m.child.Type()
+}
+
+func (m Min) Name() string {
+	return "min(" + m.child.Name() + ")"
+}
+
+func (m Min) Eval(row sql.Row) (interface{}, error) {
+	return nil, evalAggregation(m.Name())
+}
+
+func (m Min) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	child, err := m.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewMin(child))
+}
+
+func (m Min) NewBuffer() sql.AggregationBuffer {
+	return &extremeBuffer{child: m.child, sign: -1}
+}
+
+// Max returns the greatest value of its child over the rows of a group.
+type Max struct {
+	child sql.Expression
+}
+
+func NewMax(child sql.Expression) *Max {
+	return &Max{child: child}
+}
+
+func (m Max) Type() sql.Type {
+	return m.child.Type()
+}
+
+func (m Max) Name() string {
+	return "max(" + m.child.Name() + ")"
+}
+
+func (m Max) Eval(row sql.Row) (interface{}, error) {
+	return nil, evalAggregation(m.Name())
+}
+
+func (m Max) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	child, err := m.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewMax(child))
+}
+
+func (m Max) NewBuffer() sql.AggregationBuffer {
+	return &extremeBuffer{child: m.child, sign: 1}
+}
+
+// extremeBuffer keeps the value v for which sign * Compare(v, other) > 0
+// against every other value seen.
+type extremeBuffer struct {
+	child sql.Expression
+	sign  int
+	value interface{}
+}
+
+func (b *extremeBuffer) Update(row sql.Row) error {
+	v, err := b.child.Eval(row)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	if b.value == nil || b.sign*b.child.Type().Compare(v, b.value) > 0 {
+		b.value = v
+	}
+
+	return nil
+}
+
+func (b *extremeBuffer) Eval() (interface{}, error) {
+	return b.value, nil
+}
@@ -0,0 +1,63 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/mvader/gitql/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregations(t *testing.T) {
+	require := require.New(t)
+	rows := []sql.Row{
+		sql.NewMemoryRow("a", int32(3), int64(100), float64(0.5)),
+		sql.NewMemoryRow("b", int32(1), int64(300), float64(1.5)),
+		sql.NewMemoryRow("a", int32(8), int64(200), float64(1)),
+	}
+
+	str := NewGetField(0, sql.String, "col1")
+	integer := NewGetField(1, sql.Integer, "col2")
+	ts := NewGetField(2, sql.Timestamp, "col3")
+	float := NewGetField(3, sql.Float, "col4")
+
+	cases := []struct {
+		aggregation sql.Aggregation
+		name        string
+		typ         sql.Type
+		expected    interface{}
+	}{
+		{NewCount(NewStar()), "count(*)", sql.BigInteger, int64(3)},
+		{NewCount(str), "count(col1)", sql.BigInteger, int64(3)},
+		{NewCountDistinct(str), "count(distinct col1)", sql.BigInteger, int64(2)},
+		{NewSum(integer), "sum(col2)", sql.BigInteger, int64(12)},
+		{NewSum(ts), "sum(col3)", sql.BigInteger, int64(600)},
+		{NewAvg(integer), "avg(col2)", sql.Float, float64(4)},
+		{NewSum(float), "sum(col4)", sql.Float, float64(3)},
+		{NewAvg(float), "avg(col4)", sql.Float, float64(1)},
+		{NewAvg(ts), "avg(col3)", sql.Timestamp, int64(200)},
+		{NewMin(integer), "min(col2)", sql.Integer, int32(1)},
+		{NewMin(str), "min(col1)", sql.String, "a"},
+		{NewMax(integer), "max(col2)", sql.Integer, int32(8)},
+		{NewMax(ts), "max(col3)", sql.Timestamp, int64(300)},
+	}
+
+	for _, c := range cases {
+		require.Equal(c.name, c.aggregation.Name())
+		require.Equal(c.typ, c.aggregation.Type(), c.name)
+
+		_, err := c.aggregation.Eval(rows[0])
+		require.NotNil(err)
+
+		b := c.aggregation.NewBuffer()
+		for _, row := range rows {
+			require.Nil(b.Update(row))
+		}
+
+		v, err := b.Eval()
+		require.Nil(err)
+		require.Equal(c.expected, v, c.name)
+	}
+
+	err := NewSum(str).NewBuffer().Update(rows[0])
+	require.NotNil(err)
+}
@@ -0,0 +1,38 @@
+package expression
+
+import "github.com/mvader/gitql/sql"
+
+// Alias gives a name to the value of its child.
+type Alias struct {
+	child sql.Expression
+	name  string
+}
+
+func NewAlias(child sql.Expression, name string) *Alias {
+	return &Alias{child: child, name: name}
+}
+
+func (e Alias) Child() sql.Expression {
+	return e.child
+}
+
+func (e Alias) Type() sql.Type {
+	return e.child.Type()
+}
+
+func (e Alias) Eval(row sql.Row) (interface{}, error) {
+	return e.child.Eval(row)
+}
+
+func (e Alias) Name() string {
+	return e.name
+}
+
+func (e Alias) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	c, err := e.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewAlias(c, e.name))
+}
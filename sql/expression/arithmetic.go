@@ -0,0 +1,284 @@
+package expression
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mvader/gitql/sql"
+)
+
+// ErrDivisionByZero is returned when the right operand of a division or a
+// modulo is zero.
+var ErrDivisionByZero = errors.New("division by zero")
+
+// Arithmetic applies one of the +, -, *, / and % operators to two numeric
+// values. The type of the result is promoted from the types of the operands.
+// If any of the operands is NULL the result is NULL.
+type Arithmetic struct {
+	left  sql.Expression
+	right sql.Expression
+	op    string
+}
+
+func NewArithmetic(left sql.Expression, right sql.Expression, op string) *Arithmetic {
+	return &Arithmetic{left, right, op}
+}
+
+func NewPlus(left sql.Expression, right sql.Expression) *Arithmetic {
+	return NewArithmetic(left, right, "+")
+}
+
+func NewMinus(left sql.Expression, right sql.Expression) *Arithmetic {
+	return NewArithmetic(left, right, "-")
+}
+
+func NewMult(left sql.Expression, right sql.Expression) *Arithmetic {
+	return NewArithmetic(left, right, "*")
+}
+
+func NewDiv(left sql.Expression, right sql.Expression) *Arithmetic {
+	return NewArithmetic(left, right, "/")
+}
+
+func NewMod(left sql.Expression, right sql.Expression) *Arithmetic {
+	return NewArithmetic(left, right, "%")
+}
+
+func (a Arithmetic) Left() sql.Expression {
+	return a.left
+}
+
+func (a Arithmetic) Right() sql.Expression {
+	return a.right
+}
+
+func (a Arithmetic) Op() string {
+	return a.op
+}
+
+func (a Arithmetic) Type() sql.Type {
+	t, _ := arithmeticType(a.op, a.left.Type(), a.right.Type())
+	return t
+}
+
+func (a Arithmetic) Name() string {
+	return a.left.Name() + a.op + a.right.Name()
+}
+
+func (a Arithmetic) CheckTypes() error {
+	l, r := a.left.Type(), a.right.Type()
+	if _, ok := arithmeticType(a.op, l, r); !ok {
+		return fmt.Errorf(
+			"operator %q can't be applied to %s and %s",
+			a.op, l.Name(), r.Name(),
+		)
+	}
+	return nil
+}
+
+func (a Arithmetic) Eval(row sql.Row) (interface{}, error) {
+	l, err := a.left.Eval(row)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := a.right.Eval(row)
+	if err != nil {
+		return nil, err
+	}
+
+	if l == nil || r == nil {
+		return nil, nil
+	}
+
+	t := a.Type()
+	if t == sql.Float {
+		return a.evalFloat(l, r)
+	}
+
+	v, err := a.evalInt(l, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Convert(v)
+}
+
+func (a Arithmetic) evalInt(l, r interface{}) (interface{}, error) {
+	lv, err := sql.BigInteger.Convert(l)
+	if err != nil {
+		return nil, err
+	}
+
+	rv, err := sql.BigInteger.Convert(r)
+	if err != nil {
+		return nil, err
+	}
+
+	x, y := lv.(int64), rv.(int64)
+	switch a.op {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "%":
+		if y == 0 {
+			return nil, ErrDivisionByZero
+		}
+		return x % y, nil
+	}
+
+	return nil, fmt.Errorf("unknown arithmetic operator %q", a.op)
+}
+
+func (a Arithmetic) evalFloat(l, r interface{}) (interface{}, error) {
+	lv, err := sql.Float.Convert(l)
+	if err != nil {
+		return nil, err
+	}
+
+	rv, err := sql.Float.Convert(r)
+	if err != nil {
+		return nil, err
+	}
+
+	x, y := lv.(float64), rv.(float64)
+	switch a.op {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "/":
+		if y == 0 {
+			return nil, ErrDivisionByZero
+		}
+		return x / y, nil
+	case "%":
+		return nil, fmt.Errorf("operator %q can't be applied to %s", a.op, sql.Float.Name())
+	}
+
+	return nil, fmt.Errorf("unknown arithmetic operator %q", a.op)
+}
+
+func (a Arithmetic) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	l, err := a.left.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := a.right.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewArithmetic(l, r, a.op))
+}
+
+// arithmeticType returns the type of the result of applying the given
+// operator to values of the given types, and whether it can be applied at all.
+// Adding or subtracting integers to a timestamp gives a timestamp, and the
+// difference between two timestamps is the number of seconds between them.
+// Dividing numbers always gives a float.
+func arithmeticType(op string, l, r sql.Type) (sql.Type, bool) {
+	if l == sql.Null {
+		l = r
+	} else if r == sql.Null {
+		r = l
+	}
+
+	if l == sql.Null {
+		return sql.Null, true
+	}
+
+	if !isNumeric(l) || !isNumeric(r) {
+		return sql.BigInteger, false
+	}
+
+	if l == sql.Timestamp || r == sql.Timestamp {
+		switch {
+		case op == "-" && l == sql.Timestamp && r == sql.Timestamp:
+			return sql.BigInteger, true
+		case (op == "+" || op == "-") && isInteger(r):
+			return sql.Timestamp, true
+		case op == "+" && isInteger(l):
+			return sql.Timestamp, true
+		}
+		return sql.Timestamp, false
+	}
+
+	if l == sql.Float || r == sql.Float || op == "/" {
+		return sql.Float, op != "%"
+	}
+
+	if l == sql.BigInteger || r == sql.BigInteger {
+		return sql.BigInteger, true
+	}
+
+	return sql.Integer, true
+}
+
+func isInteger(t sql.Type) bool {
+	return t == sql.Integer || t == sql.BigInteger
+}
+
+func isNumeric(t sql.Type) bool {
+	return isInteger(t) || t == sql.Float || t == sql.Timestamp
+}
+
+// UnaryMinus negates a numeric value. The negation of NULL is NULL.
+type UnaryMinus struct {
+	child sql.Expression
+}
+
+func NewUnaryMinus(child sql.Expression) *UnaryMinus {
+	return &UnaryMinus{child}
+}
+
+func (e UnaryMinus) Type() sql.Type {
+	return e.child.Type()
+}
+
+func (e UnaryMinus) Name() string {
+	return "-" + e.child.Name()
+}
+
+func (e UnaryMinus) CheckTypes() error {
+	t := e.child.Type()
+	if !isInteger(t) && t != sql.Float && t != sql.Null {
+		return fmt.Errorf("operator \"-\" can't be applied to %s", t.Name())
+	}
+	return nil
+}
+
+func (e UnaryMinus) Eval(row sql.Row) (interface{}, error) {
+	v, err := e.child.Eval(row)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n := v.(type) {
+	case int32:
+		return -n, nil
+	case int64:
+		return -n, nil
+	case float64:
+		return -n, nil
+	case nil:
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("operator \"-\" can't be applied to %T", v)
+}
+
+func (e UnaryMinus) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	c, err := e.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewUnaryMinus(c))
+}
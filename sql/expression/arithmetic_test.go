@@ -0,0 +1,92 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/mvader/gitql/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArithmetic(t *testing.T) {
+	require := require.New(t)
+	row := sql.NewMemoryRow(int32(7), int64(2), int64(1500000600), int64(1500000000))
+	i32 := NewGetField(0, sql.Integer, "i32")
+	i64 := NewGetField(1, sql.BigInteger, "i64")
+	committed := NewGetField(2, sql.Timestamp, "committed")
+	authored := NewGetField(3, sql.Timestamp, "authored")
+
+	testCases := []struct {
+		name     string
+		expr     sql.Expression
+		typ      sql.Type
+		expected interface{}
+	}{
+		{"int32 + int32", NewPlus(i32, i32), sql.Integer, int32(14)},
+		{"int32 + int64", NewPlus(i32, i64), sql.BigInteger, int64(9)},
+		{"int32 - int64", NewMinus(i32, i64), sql.BigInteger, int64(5)},
+		{"int32 * int64", NewMult(i32, i64), sql.BigInteger, int64(14)},
+		{"int32 / int64", NewDiv(i32, i64), sql.Float, float64(3.5)},
+		{"int64 * float", NewMult(i64, NewLiteral(float64(1.25), sql.Float)), sql.Float, float64(2.5)},
+		{"int32 % int64", NewMod(i32, i64), sql.BigInteger, int64(1)},
+		{"timestamp - timestamp", NewMinus(committed, authored), sql.BigInteger, int64(600)},
+		{"timestamp + int", NewPlus(authored, i64), sql.Timestamp, int64(1500000002)},
+		{"unary minus", NewUnaryMinus(i32), sql.Integer, int32(-7)},
+		{"precedence", NewPlus(i32, NewMult(i64, i64)), sql.BigInteger, int64(11)},
+	}
+
+	for _, tt := range testCases {
+		require.Nil(tt.expr.(typeChecker).CheckTypes(), tt.name)
+		require.Equal(tt.typ, tt.expr.Type(), tt.name)
+		require.Equal(tt.expected, eval(t, tt.expr, row), tt.name)
+	}
+}
+
+func TestArithmetic_DivisionByZero(t *testing.T) {
+	require := require.New(t)
+	row := sql.NewMemoryRow(int64(1))
+	zero := NewLiteral(int64(0), sql.BigInteger)
+
+	_, err := NewDiv(NewGetField(0, sql.BigInteger, "n"), zero).Eval(row)
+	require.Equal(ErrDivisionByZero, err)
+
+	_, err = NewMod(NewGetField(0, sql.BigInteger, "n"), zero).Eval(row)
+	require.Equal(ErrDivisionByZero, err)
+}
+
+func TestArithmetic_CheckTypes(t *testing.T) {
+	require := require.New(t)
+	str := NewGetField(0, sql.String, "s")
+	ts := NewGetField(1, sql.Timestamp, "t")
+	n := NewGetField(2, sql.BigInteger, "n")
+
+	require.EqualError(
+		NewPlus(str, n).CheckTypes(),
+		`operator "+" can't be applied to string and biginteger`,
+	)
+	require.EqualError(
+		NewPlus(ts, ts).CheckTypes(),
+		`operator "+" can't be applied to timestamp and timestamp`,
+	)
+	require.NotNil(NewMult(ts, n).CheckTypes())
+	require.NotNil(NewUnaryMinus(str).CheckTypes())
+}
+
+type typeChecker interface {
+	CheckTypes() error
+}
+
+func TestArithmetic_Null(t *testing.T) {
+	require := require.New(t)
+	row := sql.NewMemoryRow(int64(1), nil)
+	n := NewGetField(0, sql.BigInteger, "n")
+	null := NewGetField(1, sql.BigInteger, "null")
+
+	require.Nil(eval(t, NewPlus(n, null), row))
+	require.Nil(eval(t, NewDiv(n, null), row))
+	require.Nil(eval(t, NewUnaryMinus(null), row))
+
+	e := NewPlus(n, NewLiteral(nil, sql.Null))
+	require.Nil(e.CheckTypes())
+	require.Equal(sql.BigInteger, e.Type())
+	require.Nil(eval(t, e, row))
+}
@@ -1,6 +1,10 @@
 package expression
 
-import "github.com/mvader/gitql/sql"
+import (
+	"fmt"
+
+	"github.com/mvader/gitql/sql"
+)
 
 type Not struct {
 	child sql.Expression
@@ -14,10 +18,203 @@ func (e Not) Type() sql.Type {
 	return sql.Boolean
 }
 
-func (e Not) Eval(row sql.Row) interface{} {
-	return !e.child.Eval(row).(bool)
+func (e Not) Eval(row sql.Row) (interface{}, error) {
+	v, err := evalBool(e.child, row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	return !v.(bool), nil
 }
 
 func (e Not) Name() string {
 	return "Not(" + e.child.Name() + ")"
 }
+
+func (e Not) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	c, err := e.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := NewNot(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(n)
+}
+
+// logical is the common part of the binary boolean connectives.
+type logical struct {
+	left  sql.Expression
+	right sql.Expression
+}
+
+func (l logical) Left() sql.Expression {
+	return l.left
+}
+
+func (l logical) Right() sql.Expression {
+	return l.right
+}
+
+func (l logical) Type() sql.Type {
+	return sql.Boolean
+}
+
+func (l logical) transformChildren(
+	f sql.TransformExprFunc,
+) (sql.Expression, sql.Expression, error) {
+	left, err := l.left.TransformUp(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	right, err := l.right.TransformUp(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return left, right, nil
+}
+
+// evalBool evaluates an operand of a boolean connective, which is either a
+// boolean or nil for NULL.
+func evalBool(e sql.Expression, row sql.Row) (interface{}, error) {
+	v, err := e.Eval(row)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := v.(bool); !ok && v != nil {
+		return nil, fmt.Errorf("expression %q must be boolean, %T received", e.Name(), v)
+	}
+
+	return v, nil
+}
+
+// And is true if both of its operands are true and false if any of them is
+// false, otherwise it is NULL. The right operand is not evaluated if the
+// left one is false.
+type And struct {
+	logical
+}
+
+func NewAnd(left sql.Expression, right sql.Expression) *And {
+	return &And{logical{left, right}}
+}
+
+func (e And) Eval(row sql.Row) (interface{}, error) {
+	l, err := evalBool(e.left, row)
+	if err != nil || l == false {
+		return l, err
+	}
+
+	r, err := evalBool(e.right, row)
+	if err != nil || r == false {
+		return r, err
+	}
+
+	if l == nil || r == nil {
+		return nil, nil
+	}
+
+	return true, nil
+}
+
+func (e And) Name() string {
+	return e.left.Name() + " AND " + e.right.Name()
+}
+
+func (e And) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	l, r, err := e.transformChildren(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewAnd(l, r))
+}
+
+// Or is true if any of its operands is true and false if both of them are
+// false, otherwise it is NULL. The right operand is not evaluated if the
+// left one is true.
+type Or struct {
+	logical
+}
+
+func NewOr(left sql.Expression, right sql.Expression) *Or {
+	return &Or{logical{left, right}}
+}
+
+func (e Or) Eval(row sql.Row) (interface{}, error) {
+	l, err := evalBool(e.left, row)
+	if err != nil || l == true {
+		return l, err
+	}
+
+	r, err := evalBool(e.right, row)
+	if err != nil || r == true {
+		return r, err
+	}
+
+	if l == nil || r == nil {
+		return nil, nil
+	}
+
+	return false, nil
+}
+
+func (e Or) Name() string {
+	return e.left.Name() + " OR " + e.right.Name()
+}
+
+func (e Or) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	l, r, err := e.transformChildren(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewOr(l, r))
+}
+
+// Xor is true if exactly one of its operands is true, or NULL if any of them
+// is NULL.
+type Xor struct {
+	logical
+}
+
+func NewXor(left sql.Expression, right sql.Expression) *Xor {
+	return &Xor{logical{left, right}}
+}
+
+func (e Xor) Eval(row sql.Row) (interface{}, error) {
+	l, err := evalBool(e.left, row)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := evalBool(e.right, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if l == nil || r == nil {
+		return nil, nil
+	}
+
+	return l != r, nil
+}
+
+func (e Xor) Name() string {
+	return e.left.Name() + " XOR " + e.right.Name()
+}
+
+func (e Xor) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	l, r, err := e.transformChildren(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewXor(l, r))
+}
@@ -0,0 +1,98 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/mvader/gitql/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogical(t *testing.T) {
+	require := require.New(t)
+	row := sql.NewMemoryRow()
+	t1 := NewLiteral(true, sql.Boolean)
+	f := NewLiteral(false, sql.Boolean)
+
+	testCases := []struct {
+		name     string
+		expr     sql.Expression
+		expected bool
+	}{
+		{"true and true", NewAnd(t1, t1), true},
+		{"true and false", NewAnd(t1, f), false},
+		{"false or true", NewOr(f, t1), true},
+		{"false or false", NewOr(f, f), false},
+		{"true xor true", NewXor(t1, t1), false},
+		{"true xor false", NewXor(t1, f), true},
+	}
+
+	for _, tt := range testCases {
+		require.Equal(sql.Boolean, tt.expr.Type(), tt.name)
+		require.Equal(tt.expected, eval(t, tt.expr, row), tt.name)
+	}
+}
+
+func TestLogical_ShortCircuit(t *testing.T) {
+	require := require.New(t)
+	row := sql.NewMemoryRow()
+	// unresolved identifiers fail when they are evaluated
+	unresolved := NewIdentifier("foo")
+
+	require.Equal(false, eval(t, NewAnd(NewLiteral(false, sql.Boolean), unresolved), row))
+	require.Equal(true, eval(t, NewOr(NewLiteral(true, sql.Boolean), unresolved), row))
+
+	_, err := NewXor(NewLiteral(true, sql.Boolean), unresolved).Eval(row)
+	require.NotNil(err)
+
+	_, err = NewAnd(NewLiteral(true, sql.Boolean), NewLiteral("foo", sql.String)).Eval(row)
+	require.NotNil(err)
+}
+
+func TestLogical_Null(t *testing.T) {
+	require := require.New(t)
+	row := sql.NewMemoryRow()
+	t1 := NewLiteral(true, sql.Boolean)
+	f := NewLiteral(false, sql.Boolean)
+	null := NewLiteral(nil, sql.Null)
+
+	testCases := []struct {
+		name     string
+		expr     sql.Expression
+		expected interface{}
+	}{
+		{"not null", mustNot(NewNot(null)), nil},
+		{"null and true", NewAnd(null, t1), nil},
+		{"null and false", NewAnd(null, f), false},
+		{"false and null", NewAnd(f, null), false},
+		{"null or true", NewOr(null, t1), true},
+		{"null or false", NewOr(null, f), nil},
+		{"true or null", NewOr(t1, null), true},
+		{"null xor true", NewXor(null, t1), nil},
+		{"null = null", NewEquals(null, null), nil},
+		{"1 < null", NewLessThan(NewLiteral(int64(1), sql.BigInteger), null), nil},
+		{"null is null", NewIsNull(null), true},
+		{"true is null", NewIsNull(t1), false},
+	}
+
+	for _, tt := range testCases {
+		require.Equal(tt.expected, eval(t, tt.expr, row), tt.name)
+	}
+}
+
+func TestNot_NonBoolean(t *testing.T) {
+	require := require.New(t)
+	row := sql.NewMemoryRow("foo", int32(1))
+
+	_, err := mustNot(NewNot(NewGetField(0, sql.String, "col1"))).Eval(row)
+	require.EqualError(err, `expression "col1" must be boolean, string received`)
+
+	_, err = mustNot(NewNot(NewGetField(1, sql.Integer, "col2"))).Eval(row)
+	require.EqualError(err, `expression "col2" must be boolean, int32 received`)
+}
+
+func mustNot(e *Not, err error) *Not {
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
@@ -0,0 +1,210 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mvader/gitql/sql"
+)
+
+// CaseBranch is a WHEN ... THEN ... branch of a Case expression.
+type CaseBranch struct {
+	Cond  sql.Expression
+	Value sql.Expression
+}
+
+// Case evaluates to the value of its first branch whose condition is true,
+// or to its ELSE value if none is, or to NULL if it has no ELSE. If it has an
+// expression, the conditions of the branches are instead values compared to
+// it. The type of the result is unified from the types of all the values.
+type Case struct {
+	expr     sql.Expression
+	branches []CaseBranch
+	elseExpr sql.Expression
+}
+
+// NewCase returns a Case with the given branches. expr is nil for a searched
+// CASE, and elseExpr is nil if there is no ELSE.
+func NewCase(expr sql.Expression, branches []CaseBranch, elseExpr sql.Expression) *Case {
+	return &Case{expr, branches, elseExpr}
+}
+
+func (c Case) Expr() sql.Expression {
+	return c.expr
+}
+
+func (c Case) Branches() []CaseBranch {
+	return c.branches
+}
+
+func (c Case) Else() sql.Expression {
+	return c.elseExpr
+}
+
+func (c Case) Type() sql.Type {
+	t, _ := c.resultType()
+	return t
+}
+
+func (c Case) Name() string {
+	parts := []string{"CASE"}
+	if c.expr != nil {
+		parts = append(parts, c.expr.Name())
+	}
+
+	for _, b := range c.branches {
+		parts = append(parts, "WHEN", b.Cond.Name(), "THEN", b.Value.Name())
+	}
+
+	if c.elseExpr != nil {
+		parts = append(parts, "ELSE", c.elseExpr.Name())
+	}
+
+	return strings.Join(append(parts, "END"), " ")
+}
+
+// CheckTypes returns an error if the conditions of a searched CASE are not
+// boolean or if the types of the values can't be unified.
+func (c Case) CheckTypes() error {
+	if c.expr == nil {
+		for _, b := range c.branches {
+			if t := b.Cond.Type(); t != sql.Boolean && t != sql.Null {
+				return fmt.Errorf(
+					"condition %q of CASE must be %s, %s received",
+					b.Cond.Name(), sql.Boolean.Name(), t.Name(),
+				)
+			}
+		}
+	}
+
+	_, err := c.resultType()
+	return err
+}
+
+func (c Case) Eval(row sql.Row) (interface{}, error) {
+	for _, b := range c.branches {
+		ok, err := c.matches(b.Cond, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			return c.evalValue(b.Value, row)
+		}
+	}
+
+	if c.elseExpr == nil {
+		return nil, nil
+	}
+
+	return c.evalValue(c.elseExpr, row)
+}
+
+func (c Case) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	var expr, elseExpr sql.Expression
+	var err error
+	if c.expr != nil {
+		expr, err = c.expr.TransformUp(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var branches []CaseBranch
+	for _, b := range c.branches {
+		cond, err := b.Cond.TransformUp(f)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := b.Value.TransformUp(f)
+		if err != nil {
+			return nil, err
+		}
+
+		branches = append(branches, CaseBranch{Cond: cond, Value: value})
+	}
+
+	if c.elseExpr != nil {
+		elseExpr, err = c.elseExpr.TransformUp(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return f(NewCase(expr, branches, elseExpr))
+}
+
+// matches reports whether the given condition of a branch is true or, if the
+// CASE has an expression, whether it is equal to it.
+func (c Case) matches(cond sql.Expression, row sql.Row) (bool, error) {
+	if c.expr == nil {
+		v, err := cond.Eval(row)
+		return v == true, err
+	}
+
+	cmp, ok, err := comparison{left: c.expr, right: cond}.compare(row)
+	return ok && cmp == 0, err
+}
+
+func (c Case) evalValue(e sql.Expression, row sql.Row) (interface{}, error) {
+	v, err := e.Eval(row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	t, err := c.resultType()
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Convert(v)
+}
+
+// resultType returns the type the values of all the branches and the ELSE
+// can be converted to.
+func (c Case) resultType() (sql.Type, error) {
+	values := make([]sql.Expression, 0, len(c.branches)+1)
+	for _, b := range c.branches {
+		values = append(values, b.Value)
+	}
+
+	if c.elseExpr != nil {
+		values = append(values, c.elseExpr)
+	}
+
+	var result sql.Type = sql.Null
+	for _, v := range values {
+		t, ok := unifyTypes(result, v.Type())
+		if !ok {
+			return result, fmt.Errorf(
+				"values of CASE can't be both %s and %s",
+				result.Name(), v.Type().Name(),
+			)
+		}
+		result = t
+	}
+
+	return result, nil
+}
+
+// unifyTypes returns the type values of both given types can be converted to.
+// NULL can be converted to any type, and numbers to the widest of both types.
+// Timestamps are only unified with other timestamps.
+func unifyTypes(a, b sql.Type) (sql.Type, bool) {
+	switch {
+	case a == b || b == sql.Null:
+		return a, true
+	case a == sql.Null:
+		return b, true
+	case a == sql.Timestamp || b == sql.Timestamp:
+		return a, false
+	case isNumeric(a) && isNumeric(b):
+		if a == sql.Float || b == sql.Float {
+			return sql.Float, true
+		}
+		return sql.BigInteger, true
+	}
+
+	return a, false
+}
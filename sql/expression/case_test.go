@@ -0,0 +1,72 @@
+package expression
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mvader/gitql/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCase(t *testing.T) {
+	require := require.New(t)
+	msg := NewGetField(0, sql.String, "message")
+	e := NewCase(nil, []CaseBranch{
+		{
+			Cond:  NewRegexp(msg, NewLiteral("^Merge", sql.String)),
+			Value: NewLiteral("merge", sql.String),
+		},
+		{
+			Cond:  NewRegexp(msg, NewLiteral("^Revert", sql.String)),
+			Value: NewLiteral("revert", sql.String),
+		},
+	}, NewLiteral("feature", sql.String))
+	require.Nil(e.CheckTypes())
+	require.Equal(sql.String, e.Type())
+
+	require.Equal("merge", eval(t, e, sql.NewMemoryRow("Merge branch 'foo'")))
+	require.Equal("revert", eval(t, e, sql.NewMemoryRow("Revert \"add foo\"")))
+	require.Equal("feature", eval(t, e, sql.NewMemoryRow("add foo")))
+	require.Equal("feature", eval(t, e, sql.NewMemoryRow(nil)))
+}
+
+func TestCase_Simple(t *testing.T) {
+	require := require.New(t)
+	e := NewCase(NewGetField(0, sql.Integer, "n"), []CaseBranch{
+		{Cond: NewLiteral(int64(1), sql.BigInteger), Value: NewLiteral("one", sql.String)},
+		{Cond: NewLiteral(int64(2), sql.BigInteger), Value: NewLiteral("two", sql.String)},
+	}, nil)
+	require.Equal("CASE n WHEN literal_biginteger THEN literal_string WHEN literal_biginteger THEN literal_string END", e.Name())
+
+	require.Equal("two", eval(t, e, sql.NewMemoryRow(int32(2))))
+	require.Nil(eval(t, e, sql.NewMemoryRow(int32(3))))
+	require.Nil(eval(t, e, sql.NewMemoryRow(nil)))
+}
+
+func TestCase_Types(t *testing.T) {
+	require := require.New(t)
+	cond := NewGetField(0, sql.Boolean, "cond")
+
+	e := NewCase(nil, []CaseBranch{
+		{Cond: cond, Value: NewLiteral(int32(1), sql.Integer)},
+		{Cond: cond, Value: NewLiteral(nil, sql.Null)},
+	}, NewLiteral(int64(2), sql.BigInteger))
+	require.Nil(e.CheckTypes())
+	require.Equal(sql.BigInteger, e.Type())
+	require.Equal(int64(1), eval(t, e, sql.NewMemoryRow(true)))
+
+	e = NewCase(nil, []CaseBranch{
+		{Cond: cond, Value: NewLiteral(int32(1), sql.Integer)},
+	}, NewLiteral("foo", sql.String))
+	require.EqualError(e.CheckTypes(), "values of CASE can't be both integer and string")
+
+	e = NewCase(nil, []CaseBranch{
+		{Cond: cond, Value: NewLiteral(int32(1), sql.Integer)},
+	}, NewLiteral(time.Unix(0, 0), sql.Timestamp))
+	require.EqualError(e.CheckTypes(), "values of CASE can't be both integer and timestamp")
+
+	e = NewCase(nil, []CaseBranch{
+		{Cond: NewLiteral("foo", sql.String), Value: NewLiteral(int32(1), sql.Integer)},
+	}, nil)
+	require.NotNil(e.CheckTypes())
+}
@@ -0,0 +1,57 @@
+package expression
+
+import (
+	"time"
+
+	"github.com/mvader/gitql/sql"
+)
+
+// Cast converts the value of its child to the given type, using the Convert
+// method of the type. Timestamps are cast to strings as times in UTC instead
+// of as numbers of seconds. Casting NULL results in NULL.
+type Cast struct {
+	child sql.Expression
+	typ   sql.Type
+}
+
+func NewCast(child sql.Expression, typ sql.Type) *Cast {
+	return &Cast{child, typ}
+}
+
+func (c Cast) Child() sql.Expression {
+	return c.child
+}
+
+func (c Cast) Type() sql.Type {
+	return c.typ
+}
+
+func (c Cast) Name() string {
+	return "CAST(" + c.child.Name() + " AS " + c.typ.Name() + ")"
+}
+
+func (c Cast) Eval(row sql.Row) (interface{}, error) {
+	v, err := c.child.Eval(row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	if c.child.Type() == sql.Timestamp && c.typ == sql.String {
+		ts, err := sql.Timestamp.Convert(v)
+		if err != nil {
+			return nil, err
+		}
+		v = time.Unix(ts.(int64), 0).UTC()
+	}
+
+	return c.typ.Convert(v)
+}
+
+func (c Cast) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	child, err := c.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewCast(child, c.typ))
+}
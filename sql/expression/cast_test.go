@@ -0,0 +1,56 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/mvader/gitql/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCast(t *testing.T) {
+	require := require.New(t)
+	e := NewCast(NewGetField(0, sql.String, "s"), sql.BigInteger)
+	require.Equal(sql.BigInteger, e.Type())
+	require.Equal("CAST(s AS biginteger)", e.Name())
+
+	require.Equal(int64(42), eval(t, e, sql.NewMemoryRow("42")))
+	require.Nil(eval(t, e, sql.NewMemoryRow(nil)))
+
+	_, err := NewCast(NewGetField(0, sql.BigInteger, "n"), sql.Integer).
+		Eval(sql.NewMemoryRow(int64(1 << 40)))
+	require.EqualError(err, "value 1099511627776 overflows int32")
+}
+
+func TestCast_Conversions(t *testing.T) {
+	require := require.New(t)
+
+	testCases := []struct {
+		name     string
+		from     sql.Type
+		value    interface{}
+		to       sql.Type
+		expected interface{}
+	}{
+		{"integer to string", sql.Integer, int32(-7), sql.String, "-7"},
+		{"biginteger to string", sql.BigInteger, int64(1 << 40), sql.String, "1099511627776"},
+		{"float to string", sql.Float, 2.5, sql.String, "2.5"},
+		{"boolean to string", sql.Boolean, false, sql.String, "false"},
+		{"timestamp to string", sql.Timestamp, int64(1489660200), sql.String, "2017-03-16T10:30:00Z"},
+		{"string to boolean", sql.String, "true", sql.Boolean, true},
+		{"numeric string to boolean", sql.String, "0", sql.Boolean, false},
+		{"integer to boolean", sql.Integer, int32(3), sql.Boolean, true},
+		{"float to boolean", sql.Float, 0.0, sql.Boolean, false},
+		{"string to timestamp", sql.String, "2017-03-16 10:30:00", sql.Timestamp, int64(1489660200)},
+		{"float to integer", sql.Float, 1.9, sql.Integer, int32(1)},
+		{"negative float to biginteger", sql.Float, -1.9, sql.BigInteger, int64(-1)},
+	}
+
+	for _, tt := range testCases {
+		e := NewCast(NewGetField(0, tt.from, "v"), tt.to)
+		require.Equal(tt.expected, eval(t, e, sql.NewMemoryRow(tt.value)), tt.name)
+	}
+
+	_, err := NewCast(NewGetField(0, sql.String, "s"), sql.Boolean).
+		Eval(sql.NewMemoryRow("maybe"))
+	require.EqualError(err, `value "maybe" can't be converted to bool`)
+}
@@ -2,26 +2,267 @@ package expression
 
 import "github.com/mvader/gitql/sql"
 
-type Equals struct {
+// comparison is the common part of the expressions that compare two values.
+// Both values are converted to the type of the comparison before comparing
+// them with it. Comparing NULL with anything results in NULL.
+type comparison struct {
 	left  sql.Expression
 	right sql.Expression
 }
 
-func NewEquals(left sql.Expression, right sql.Expression) *Equals {
-	return &Equals{
-		left:  left,
-		right: right,
-	}
+func (c comparison) Left() sql.Expression {
+	return c.left
 }
 
-func (e Equals) Type() sql.Type {
+func (c comparison) Right() sql.Expression {
+	return c.right
+}
+
+func (c comparison) Type() sql.Type {
 	return sql.Boolean
 }
 
-func (e Equals) Eval(row sql.Row) interface{} {
-	return e.left.Eval(row) == e.right.Eval(row)
+// compareType returns the type used to compare both values, which is the
+// type of the left one unless it is a literal, whose type is only a guess
+// made by the parser. Numbers are compared as the widest of both types, so
+// that no value overflows when it is converted.
+func (c comparison) compareType() sql.Type {
+	l, r := c.left.Type(), c.right.Type()
+	if isNumeric(l) && isNumeric(r) {
+		if t, ok := unifyTypes(l, r); ok {
+			return t
+		}
+	}
+
+	if _, ok := c.left.(*Literal); ok {
+		return c.right.Type()
+	}
+	return c.left.Type()
+}
+
+// compare returns the result of comparing both values. The returned boolean
+// is false if any of them is NULL.
+func (c comparison) compare(row sql.Row) (int, bool, error) {
+	l, err := c.left.Eval(row)
+	if err != nil {
+		return 0, false, err
+	}
+
+	r, err := c.right.Eval(row)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if l == nil || r == nil {
+		return 0, false, nil
+	}
+
+	t := c.compareType()
+	l, err = t.Convert(l)
+	if err != nil {
+		return 0, false, err
+	}
+
+	r, err = t.Convert(r)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return t.Compare(l, r), true, nil
+}
+
+func (c comparison) transformChildren(
+	f sql.TransformExprFunc,
+) (sql.Expression, sql.Expression, error) {
+	l, err := c.left.TransformUp(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := c.right.TransformUp(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return l, r, nil
+}
+
+type Equals struct {
+	comparison
+}
+
+func NewEquals(left sql.Expression, right sql.Expression) *Equals {
+	return &Equals{comparison{left, right}}
+}
+
+func (e Equals) Eval(row sql.Row) (interface{}, error) {
+	cmp, ok, err := e.compare(row)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return cmp == 0, nil
 }
 
 func (e Equals) Name() string {
 	return e.left.Name() + "==" + e.right.Name()
 }
+
+func (e Equals) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	l, r, err := e.transformChildren(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewEquals(l, r))
+}
+
+type NotEquals struct {
+	comparison
+}
+
+func NewNotEquals(left sql.Expression, right sql.Expression) *NotEquals {
+	return &NotEquals{comparison{left, right}}
+}
+
+func (e NotEquals) Eval(row sql.Row) (interface{}, error) {
+	cmp, ok, err := e.compare(row)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return cmp != 0, nil
+}
+
+func (e NotEquals) Name() string {
+	return e.left.Name() + "<>" + e.right.Name()
+}
+
+func (e NotEquals) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	l, r, err := e.transformChildren(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewNotEquals(l, r))
+}
+
+type GreaterThan struct {
+	comparison
+}
+
+func NewGreaterThan(left sql.Expression, right sql.Expression) *GreaterThan {
+	return &GreaterThan{comparison{left, right}}
+}
+
+func (e GreaterThan) Eval(row sql.Row) (interface{}, error) {
+	cmp, ok, err := e.compare(row)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return cmp > 0, nil
+}
+
+func (e GreaterThan) Name() string {
+	return e.left.Name() + ">" + e.right.Name()
+}
+
+func (e GreaterThan) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	l, r, err := e.transformChildren(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewGreaterThan(l, r))
+}
+
+type GreaterThanOrEqual struct {
+	comparison
+}
+
+func NewGreaterThanOrEqual(left sql.Expression, right sql.Expression) *GreaterThanOrEqual {
+	return &GreaterThanOrEqual{comparison{left, right}}
+}
+
+func (e GreaterThanOrEqual) Eval(row sql.Row) (interface{}, error) {
+	cmp, ok, err := e.compare(row)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return cmp >= 0, nil
+}
+
+func (e GreaterThanOrEqual) Name() string {
+	return e.left.Name() + ">=" + e.right.Name()
+}
+
+func (e GreaterThanOrEqual) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	l, r, err := e.transformChildren(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewGreaterThanOrEqual(l, r))
+}
+
+type LessThan struct {
+	comparison
+}
+
+func NewLessThan(left sql.Expression, right sql.Expression) *LessThan {
+	return &LessThan{comparison{left, right}}
+}
+
+func (e LessThan) Eval(row sql.Row) (interface{}, error) {
+	cmp, ok, err := e.compare(row)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return cmp < 0, nil
+}
+
+func (e LessThan) Name() string {
+	return e.left.Name() + "<" + e.right.Name()
+}
+
+func (e LessThan) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	l, r, err := e.transformChildren(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewLessThan(l, r))
+}
+
+type LessThanOrEqual struct {
+	comparison
+}
+
+func NewLessThanOrEqual(left sql.Expression, right sql.Expression) *LessThanOrEqual {
+	return &LessThanOrEqual{comparison{left, right}}
+}
+
+func (e LessThanOrEqual) Eval(row sql.Row) (interface{}, error) {
+	cmp, ok, err := e.compare(row)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return cmp <= 0, nil
+}
+
+func (e LessThanOrEqual) Name() string {
+	return e.left.Name() + "<=" + e.right.Name()
+}
+
+func (e LessThanOrEqual) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	l, r, err := e.transformChildren(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewLessThanOrEqual(l, r))
+}
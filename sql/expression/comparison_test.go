@@ -0,0 +1,40 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/mvader/gitql/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComparisons(t *testing.T) {
+	require := require.New(t)
+	row := sql.NewMemoryRow(int32(2), "b", int64(4294967298))
+	num := NewGetField(0, sql.Integer, "num")
+	str := NewGetField(1, sql.String, "str")
+
+	testCases := []struct {
+		name     string
+		expr     sql.Expression
+		expected bool
+	}{
+		{"equals", NewEquals(num, NewLiteral(int64(2), sql.BigInteger)), true},
+		{"not equals", NewNotEquals(num, NewLiteral(int64(2), sql.BigInteger)), false},
+		{"greater than", NewGreaterThan(num, NewLiteral(int64(1), sql.BigInteger)), true},
+		{"greater than or equal", NewGreaterThanOrEqual(num, NewLiteral(int64(3), sql.BigInteger)), false},
+		{"less than", NewLessThan(str, NewLiteral("c", sql.String)), true},
+		{"less than or equal", NewLessThanOrEqual(str, NewLiteral("b", sql.String)), true},
+		{"literal on the left", NewLessThan(NewLiteral(int64(1), sql.BigInteger), num), true},
+		{"out of range literal", NewEquals(num, NewLiteral(int64(3000000000), sql.BigInteger)), false},
+		{"out of range literal on the left", NewGreaterThan(NewLiteral(int64(3000000000), sql.BigInteger), num), true},
+		{"integer and big integer", NewEquals(num, NewGetField(2, sql.BigInteger, "big")), false},
+	}
+
+	for _, tt := range testCases {
+		require.Equal(sql.Boolean, tt.expr.Type(), tt.name)
+		require.Equal(tt.expected, eval(t, tt.expr, row), tt.name)
+	}
+
+	_, err := NewLessThan(num, NewLiteral("a", sql.String)).Eval(row)
+	require.NotNil(err)
+}
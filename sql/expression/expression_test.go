@@ -5,6 +5,7 @@ import (
 
 	"github.com/mvader/gitql/sql"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExpressions(t *testing.T) {
@@ -20,9 +21,45 @@ func TestExpressions(t *testing.T) {
 
 	dis := NewEquals(not, NewEquals(NewGetField(2, sql.Integer, "col3"), NewGetField(4, sql.Integer, "col5")))
 
-	assert.Equal(eq.Eval(row1), true)
-	assert.Equal(dis.Eval(row1), true)
+	assert.Equal(eval(t, eq, row1), true)
+	assert.Equal(eval(t, dis, row1), true)
 
-	assert.Equal(eq.Eval(row2), false)
-	assert.Equal(dis.Eval(row2), true)
+	assert.Equal(eval(t, eq, row2), false)
+	assert.Equal(eval(t, dis, row2), true)
+}
+
+func TestIdentifier(t *testing.T) {
+	_, err := NewIdentifier("foo").Eval(sql.NewMemoryRow("foo"))
+	require.NotNil(t, err)
+}
+
+func TestTransformUp(t *testing.T) {
+	require := require.New(t)
+	e := NewEquals(NewIdentifier("foo"), NewLiteral("bar", sql.String))
+
+	result, err := e.TransformUp(func(e sql.Expression) (sql.Expression, error) {
+		if i, ok := e.(*Identifier); ok {
+			return NewGetField(0, sql.String, i.Name()), nil
+		}
+		return e, nil
+	})
+	require.Nil(err)
+	require.Equal(
+		NewEquals(NewGetField(0, sql.String, "foo"), NewLiteral("bar", sql.String)),
+		result,
+	)
+}
+
+func eval(t *testing.T, e sql.Expression, row sql.Row) interface{} {
+	v, err := e.Eval(row)
+	require.Nil(t, err)
+	return v
+}
+
+func TestAlias(t *testing.T) {
+	require := require.New(t)
+	e := NewAlias(NewGetField(0, sql.Integer, "foo"), "bar")
+	require.Equal("bar", e.Name())
+	require.Equal(sql.Integer, e.Type())
+	require.Equal(int32(1), eval(t, e, sql.NewMemoryRow(int32(1))))
 }
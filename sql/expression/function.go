@@ -0,0 +1,249 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mvader/gitql/sql"
+)
+
+// Function is a function that can be called in a query.
+type Function struct {
+	// Name is the name used to call the function, case insensitive.
+	Name string
+	// Args are the types of the arguments of the function. A nil type
+	// accepts arguments of any type.
+	Args []sql.Type
+	// New returns the expression that calls the function with the given
+	// arguments. It is only called with as many arguments as Args.
+	New func(args ...sql.Expression) (sql.Expression, error)
+}
+
+// Registry holds the functions that can be called in queries. There may be
+// several functions with the same name as long as they take a different
+// number of arguments.
+type Registry struct {
+	functions map[string][]Function
+}
+
+func NewRegistry() *Registry {
+	return &Registry{functions: map[string][]Function{}}
+}
+
+// DefaultRegistry is the registry used by the parser. Functions registered
+// in it can be called from any query.
+var DefaultRegistry = NewRegistry()
+
+// Register adds the given functions to the registry. It fails if there is
+// already a function with the same name and number of arguments.
+func (r *Registry) Register(fns ...Function) error {
+	for _, f := range fns {
+		name := strings.ToLower(f.Name)
+		for _, g := range r.functions[name] {
+			if len(g.Args) == len(f.Args) {
+				return fmt.Errorf("function %q is already registered", name)
+			}
+		}
+		r.functions[name] = append(r.functions[name], f)
+	}
+	return nil
+}
+
+// Call returns the expression that calls the function with the given name
+// and as many arguments as the given ones.
+func (r *Registry) Call(name string, args ...sql.Expression) (sql.Expression, error) {
+	fns, ok := r.functions[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+
+	var expected []string
+	for _, f := range fns {
+		if len(args) == len(f.Args) {
+			return f.New(args...)
+		}
+		expected = append(expected, fmt.Sprint(len(f.Args)))
+	}
+
+	return nil, fmt.Errorf(
+		"function %q expects %s arguments, %d received",
+		fns[0].Name, strings.Join(expected, " or "), len(args),
+	)
+}
+
+// Func returns a Function that evaluates its arguments and calls fn with
+// their values, converted to the given types of the arguments. Its result
+// must be of the given type. If any of the arguments is NULL the result is
+// NULL and fn is not called.
+func Func(
+	name string,
+	typ sql.Type,
+	args []sql.Type,
+	fn func(args ...interface{}) (interface{}, error),
+) Function {
+	return Function{
+		Name: name,
+		Args: args,
+		New: func(exprs ...sql.Expression) (sql.Expression, error) {
+			if err := checkNoStar(name, exprs); err != nil {
+				return nil, err
+			}
+			return NewCall(name, typ, args, fn, exprs...), nil
+		},
+	}
+}
+
+// Call is a call to a Go function with the values of its arguments.
+type Call struct {
+	name     string
+	typ      sql.Type
+	argTypes []sql.Type
+	fn       func(args ...interface{}) (interface{}, error)
+	args     []sql.Expression
+}
+
+func NewCall(
+	name string,
+	typ sql.Type,
+	argTypes []sql.Type,
+	fn func(args ...interface{}) (interface{}, error),
+	args ...sql.Expression,
+) *Call {
+	return &Call{
+		name:     name,
+		typ:      typ,
+		argTypes: argTypes,
+		fn:       fn,
+		args:     args,
+	}
+}
+
+func (c Call) Type() sql.Type {
+	return c.typ
+}
+
+func (c Call) Name() string {
+	var args []string
+	for _, a := range c.args {
+		args = append(args, a.Name())
+	}
+	return c.name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (c Call) Eval(row sql.Row) (interface{}, error) {
+	var values []interface{}
+	for i, a := range c.args {
+		v, err := a.Eval(row)
+		if err != nil {
+			return nil, err
+		}
+
+		if v == nil {
+			return nil, nil
+		}
+
+		if t := c.argTypes[i]; t != nil {
+			v, err = t.Convert(v)
+			if err != nil {
+				return nil, err
+			}
+		}
+		values = append(values, v)
+	}
+
+	return c.fn(values...)
+}
+
+func (c Call) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	var args []sql.Expression
+	for _, a := range c.args {
+		ta, err := a.TransformUp(f)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, ta)
+	}
+
+	return f(NewCall(c.name, c.typ, c.argTypes, c.fn, args...))
+}
+
+// CheckTypes returns an error if any of the arguments is not of the type
+// expected by the function and can't be converted to it.
+func (c Call) CheckTypes() error {
+	for i, t := range c.argTypes {
+		if t == nil {
+			continue
+		}
+
+		if at := c.args[i].Type(); !isConvertible(at, t) {
+			return fmt.Errorf(
+				"argument %d of function %q must be %s, %s received",
+				i+1, c.name, t.Name(), at.Name(),
+			)
+		}
+	}
+	return nil
+}
+
+// isConvertible reports whether the values of type from can be passed as
+// arguments of type to. Numbers, including timestamps, can be converted to
+// each other, and strings to timestamps.
+func isConvertible(from, to sql.Type) bool {
+	switch {
+	case from == to || from == sql.Null:
+		return true
+	case isNumeric(from) && isNumeric(to):
+		return true
+	}
+
+	return from == sql.String && to == sql.Timestamp
+}
+
+func checkNoStar(name string, args []sql.Expression) error {
+	for _, a := range args {
+		if _, ok := a.(*Star); ok {
+			return fmt.Errorf(`"*" is not a valid argument for function %q`, name)
+		}
+	}
+	return nil
+}
+
+func aggregationFunction(name string, fn func(sql.Expression) sql.Expression) Function {
+	return Function{
+		Name: name,
+		Args: []sql.Type{nil},
+		New: func(args ...sql.Expression) (sql.Expression, error) {
+			if err := checkNoStar(name, args); err != nil {
+				return nil, err
+			}
+			return fn(args[0]), nil
+		},
+	}
+}
+
+func init() {
+	err := DefaultRegistry.Register(
+		Function{
+			Name: "count",
+			Args: []sql.Type{nil},
+			New: func(args ...sql.Expression) (sql.Expression, error) {
+				return NewCount(args[0]), nil
+			},
+		},
+		aggregationFunction("sum", func(e sql.Expression) sql.Expression {
+			return NewSum(e)
+		}),
+		aggregationFunction("avg", func(e sql.Expression) sql.Expression {
+			return NewAvg(e)
+		}),
+		aggregationFunction("min", func(e sql.Expression) sql.Expression {
+			return NewMin(e)
+		}),
+		aggregationFunction("max", func(e sql.Expression) sql.Expression {
+			return NewMax(e)
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,110 @@
+package expression
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mvader/gitql/sql"
+	"github.com/stretchr/testify/require"
+)
+
+var upper = Func("upper", sql.String, []sql.Type{sql.String},
+	func(args ...interface{}) (interface{}, error) {
+		return strings.ToUpper(args[0].(string)), nil
+	},
+)
+
+func TestRegistry(t *testing.T) {
+	require := require.New(t)
+	r := NewRegistry()
+	require.Nil(r.Register(upper))
+	require.NotNil(r.Register(upper))
+
+	e, err := r.Call("UPPER", NewGetField(0, sql.String, "col1"))
+	require.Nil(err)
+	require.Equal("upper(col1)", e.Name())
+	require.Equal(sql.String, e.Type())
+	require.Equal("FOO", eval(t, e, sql.NewMemoryRow("foo")))
+
+	_, err = r.Call("lower", NewGetField(0, sql.String, "col1"))
+	require.EqualError(err, `unknown function "lower"`)
+
+	_, err = r.Call("upper")
+	require.EqualError(err, `function "upper" expects 1 arguments, 0 received`)
+
+	_, err = r.Call("upper", NewStar())
+	require.NotNil(err)
+
+	prefix := Func("upper", sql.String, []sql.Type{sql.String, sql.BigInteger},
+		func(args ...interface{}) (interface{}, error) {
+			s := args[0].(string)
+			n := int(args[1].(int64))
+			return strings.ToUpper(s[:n]) + s[n:], nil
+		},
+	)
+	require.Nil(r.Register(prefix))
+
+	e, err = r.Call("upper", NewGetField(0, sql.String, "col1"), NewLiteral(int64(1), sql.BigInteger))
+	require.Nil(err)
+	require.Equal("Foo", eval(t, e, sql.NewMemoryRow("foo")))
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	require := require.New(t)
+	col := NewGetField(0, sql.Integer, "col1")
+
+	e, err := DefaultRegistry.Call("COUNT", NewStar())
+	require.Nil(err)
+	require.Equal(NewCount(NewStar()), e)
+
+	e, err = DefaultRegistry.Call("sum", col)
+	require.Nil(err)
+	require.Equal(NewSum(col), e)
+
+	_, err = DefaultRegistry.Call("max", NewStar())
+	require.NotNil(err)
+}
+
+func TestCall_CheckTypes(t *testing.T) {
+	require := require.New(t)
+
+	e, err := upper.New(NewGetField(0, sql.String, "col1"))
+	require.Nil(err)
+	require.Nil(e.(*Call).CheckTypes())
+
+	e, err = upper.New(NewGetField(0, sql.Integer, "col1"))
+	require.Nil(err)
+	require.EqualError(
+		e.(*Call).CheckTypes(),
+		`argument 1 of function "upper" must be string, integer received`,
+	)
+
+	e, err = upper.New(NewLiteral(nil, sql.Null))
+	require.Nil(err)
+	require.Nil(e.(*Call).CheckTypes())
+}
+
+func TestCall_Convert(t *testing.T) {
+	require := require.New(t)
+	double := Func("double", sql.BigInteger, []sql.Type{sql.BigInteger},
+		func(args ...interface{}) (interface{}, error) {
+			return 2 * args[0].(int64), nil
+		},
+	)
+
+	e, err := double.New(NewGetField(0, sql.Integer, "n"))
+	require.Nil(err)
+	require.Nil(e.(*Call).CheckTypes())
+	require.Equal(int64(6), eval(t, e, sql.NewMemoryRow(int32(3))))
+
+	e, err = DefaultRegistry.Call("year", NewGetField(0, sql.String, "s"))
+	require.Nil(err)
+	require.Nil(e.(*Call).CheckTypes())
+
+	e, err = DefaultRegistry.Call("year", NewGetField(0, sql.Boolean, "b"))
+	require.Nil(err)
+	require.EqualError(
+		e.(*Call).CheckTypes(),
+		`argument 1 of function "year" must be timestamp, boolean received`,
+	)
+}
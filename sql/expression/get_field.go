@@ -6,24 +6,46 @@ type GetField struct {
 	fieldIndex int
 	fieldName  string
 	fieldType  sql.Type
+	table      string
 }
 
 func NewGetField(index int, fieldType sql.Type, fieldName string) *GetField {
+	return NewGetFieldWithTable(index, "", fieldType, fieldName)
+}
+
+// NewGetFieldWithTable returns a GetField of a field that comes from the
+// relation with the given name.
+func NewGetFieldWithTable(index int, table string, fieldType sql.Type, fieldName string) *GetField {
 	return &GetField{
 		fieldIndex: index,
 		fieldType:  fieldType,
 		fieldName:  fieldName,
+		table:      table,
 	}
 }
 
+// Index returns the position of the field in the row.
+func (p GetField) Index() int {
+	return p.fieldIndex
+}
+
+// Table returns the name of the relation the field comes from, if known.
+func (p GetField) Table() string {
+	return p.table
+}
+
 func (p GetField) Type() sql.Type {
 	return p.fieldType
 }
 
-func (p GetField) Eval(row sql.Row) interface{} {
-	return row.Fields()[p.fieldIndex]
+func (p GetField) Eval(row sql.Row) (interface{}, error) {
+	return row.Fields()[p.fieldIndex], nil
 }
 
 func (p GetField) Name() string {
 	return p.fieldName
 }
+
+func (p GetField) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	return f(NewGetFieldWithTable(p.fieldIndex, p.table, p.fieldType, p.fieldName))
+}
@@ -1,22 +1,58 @@
 package expression
 
-import "github.com/mvader/gitql/sql"
+import (
+	"fmt"
 
+	"github.com/mvader/gitql/sql"
+)
+
+// Identifier is a reference to a column by its name, optionally qualified
+// with the name of the relation it belongs to. Identifiers are produced by
+// the parser and must be resolved by the analyzer before evaluation.
 type Identifier struct {
-	name string
+	table string
+	name  string
 }
 
 func NewIdentifier(name string) *Identifier {
+	return NewQualifiedIdentifier("", name)
+}
+
+// NewQualifiedIdentifier returns an Identifier of a column of the relation
+// with the given name.
+func NewQualifiedIdentifier(table, name string) *Identifier {
 	return &Identifier{
-		name: name,
+		table: table,
+		name:  name,
 	}
 }
 
+// Table returns the name of the relation that qualifies the identifier, or
+// an empty string if it is not qualified.
+func (i Identifier) Table() string {
+	return i.table
+}
+
 func (i Identifier) Type() sql.Type {
 	return sql.String
 }
 
-func (i Identifier) Eval(row sql.Row) interface{} {
-	// TODO: return real value
+func (i Identifier) Eval(row sql.Row) (interface{}, error) {
+	return nil, fmt.Errorf("identifier %q has not been resolved", i.String())
+}
+
+func (i Identifier) Name() string {
 	return i.name
 }
+
+// String returns the name of the identifier with its qualifier, if any.
+func (i Identifier) String() string {
+	if i.table == "" {
+		return i.name
+	}
+	return i.table + "." + i.name
+}
+
+func (i Identifier) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	return f(NewQualifiedIdentifier(i.table, i.name))
+}
@@ -0,0 +1,38 @@
+package expression
+
+import "github.com/mvader/gitql/sql"
+
+// IsNull is true if its child is NULL.
+type IsNull struct {
+	child sql.Expression
+}
+
+func NewIsNull(child sql.Expression) *IsNull {
+	return &IsNull{child}
+}
+
+func (e IsNull) Type() sql.Type {
+	return sql.Boolean
+}
+
+func (e IsNull) Eval(row sql.Row) (interface{}, error) {
+	v, err := e.child.Eval(row)
+	if err != nil {
+		return nil, err
+	}
+
+	return v == nil, nil
+}
+
+func (e IsNull) Name() string {
+	return e.child.Name() + " IS NULL"
+}
+
+func (e IsNull) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	c, err := e.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewIsNull(c))
+}
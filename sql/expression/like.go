@@ -0,0 +1,254 @@
+package expression
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/mvader/gitql/sql"
+)
+
+// Like matches a string against a pattern in which "%" matches any sequence
+// of characters and "_" matches a single character. Wildcards preceded by
+// the escape character, "\" unless another one is given, match themselves.
+type Like struct {
+	left    sql.Expression
+	pattern sql.Expression
+	escape  sql.Expression
+	cache   *regexpCache
+}
+
+// NewLike returns a Like matching left against the given pattern. The escape
+// expression may be nil to use the default escape character.
+func NewLike(left, pattern, escape sql.Expression) *Like {
+	return &Like{
+		left:    left,
+		pattern: pattern,
+		escape:  escape,
+		cache:   newRegexpCache(pattern, escape),
+	}
+}
+
+func (e Like) Type() sql.Type {
+	return sql.Boolean
+}
+
+func (e Like) Name() string {
+	name := e.left.Name() + " LIKE " + e.pattern.Name()
+	if e.escape != nil {
+		name += " ESCAPE " + e.escape.Name()
+	}
+	return name
+}
+
+func (e Like) CheckTypes() error {
+	return checkStrings("LIKE", e.left, e.pattern, e.escape)
+}
+
+func (e Like) Eval(row sql.Row) (interface{}, error) {
+	return matchRegexp(row, e.left, e.cache, func() (*regexp.Regexp, error) {
+		pattern, err := evalString(e.pattern, row)
+		if err != nil || pattern == nil {
+			return nil, err
+		}
+
+		escape := `\`
+		if e.escape != nil {
+			v, err := evalString(e.escape, row)
+			if err != nil || v == nil {
+				return nil, err
+			}
+			escape = v.(string)
+		}
+
+		return likeToRegexp(pattern.(string), escape)
+	})
+}
+
+func (e Like) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	left, err := e.left.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, err := e.pattern.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	escape := e.escape
+	if escape != nil {
+		escape, err = escape.TransformUp(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return f(NewLike(left, pattern, escape))
+}
+
+// likeToRegexp translates a LIKE pattern to the equivalent regular
+// expression.
+func likeToRegexp(pattern string, escape string) (*regexp.Regexp, error) {
+	esc := []rune(escape)
+	if len(esc) != 1 {
+		return nil, fmt.Errorf("LIKE escape must be a single character, %q received", escape)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`(?s)^`)
+	var escaped bool
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			buf.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == esc[0]:
+			escaped = true
+		case r == '%':
+			buf.WriteString(`.*`)
+		case r == '_':
+			buf.WriteString(`.`)
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	if escaped {
+		return nil, fmt.Errorf("LIKE pattern %q ends with the escape character", pattern)
+	}
+
+	buf.WriteString(`$`)
+	return regexp.Compile(buf.String())
+}
+
+// Regexp matches a string against a regular expression with the syntax of
+// Go's regexp package. The string matches if any part of it matches the
+// regular expression.
+type Regexp struct {
+	left    sql.Expression
+	pattern sql.Expression
+	cache   *regexpCache
+}
+
+func NewRegexp(left, pattern sql.Expression) *Regexp {
+	return &Regexp{
+		left:    left,
+		pattern: pattern,
+		cache:   newRegexpCache(pattern),
+	}
+}
+
+func (e Regexp) Type() sql.Type {
+	return sql.Boolean
+}
+
+func (e Regexp) Name() string {
+	return e.left.Name() + " REGEXP " + e.pattern.Name()
+}
+
+func (e Regexp) CheckTypes() error {
+	return checkStrings("REGEXP", e.left, e.pattern)
+}
+
+func (e Regexp) Eval(row sql.Row) (interface{}, error) {
+	return matchRegexp(row, e.left, e.cache, func() (*regexp.Regexp, error) {
+		pattern, err := evalString(e.pattern, row)
+		if err != nil || pattern == nil {
+			return nil, err
+		}
+
+		return regexp.Compile(pattern.(string))
+	})
+}
+
+func (e Regexp) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	left, err := e.left.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, err := e.pattern.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewRegexp(left, pattern))
+}
+
+// regexpCache keeps the regular expression of a pattern that is the same for
+// every row, so that it is only compiled once.
+type regexpCache struct {
+	once sync.Once
+	re   *regexp.Regexp
+	err  error
+}
+
+// newRegexpCache returns a cache if all the given expressions are literals,
+// or nil otherwise.
+func newRegexpCache(exprs ...sql.Expression) *regexpCache {
+	for _, e := range exprs {
+		if e == nil {
+			continue
+		}
+
+		if _, ok := e.(*Literal); !ok {
+			return nil
+		}
+	}
+	return &regexpCache{}
+}
+
+// matchRegexp matches the value of left against the regular expression
+// returned by compile, which is only called once if there is a cache. The
+// result is NULL if the value or the regular expression are NULL.
+func matchRegexp(
+	row sql.Row,
+	left sql.Expression,
+	cache *regexpCache,
+	compile func() (*regexp.Regexp, error),
+) (interface{}, error) {
+	v, err := evalString(left, row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	var re *regexp.Regexp
+	if cache != nil {
+		cache.once.Do(func() {
+			cache.re, cache.err = compile()
+		})
+		re, err = cache.re, cache.err
+	} else {
+		re, err = compile()
+	}
+
+	if err != nil || re == nil {
+		return nil, err
+	}
+
+	return re.MatchString(v.(string)), nil
+}
+
+func evalString(e sql.Expression, row sql.Row) (interface{}, error) {
+	v, err := e.Eval(row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	return sql.String.Convert(v)
+}
+
+func checkStrings(op string, exprs ...sql.Expression) error {
+	for _, e := range exprs {
+		if e == nil {
+			continue
+		}
+
+		if t := e.Type(); t != sql.String && t != sql.Null {
+			return fmt.Errorf("operator %s can't be applied to %s", op, t.Name())
+		}
+	}
+	return nil
+}
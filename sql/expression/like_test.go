@@ -0,0 +1,76 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/mvader/gitql/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLike(t *testing.T) {
+	require := require.New(t)
+
+	testCases := []struct {
+		value    string
+		pattern  string
+		escape   sql.Expression
+		expected bool
+	}{
+		{"Revert \"foo\"", "Revert%", nil, true},
+		{"fix JIRA-123", "%JIRA-___", nil, true},
+		{"fix JIRA-1234", "%JIRA-___", nil, false},
+		{"line\nrevert", "%revert", nil, true},
+		{"a.b", "a.b", nil, true},
+		{"axb", "a.b", nil, false},
+		{"100%", `100\%`, nil, true},
+		{"1000", `100\%`, nil, false},
+		{"a_b", "a!_b", NewLiteral("!", sql.String), true},
+		{"axb", "a!_b", NewLiteral("!", sql.String), false},
+	}
+
+	for _, tt := range testCases {
+		e := NewLike(
+			NewGetField(0, sql.String, "message"),
+			NewLiteral(tt.pattern, sql.String),
+			tt.escape,
+		)
+		require.NotNil(e.cache)
+		row := sql.NewMemoryRow(tt.value)
+		require.Equal(tt.expected, eval(t, e, row), tt.value+" LIKE "+tt.pattern)
+	}
+}
+
+func TestLike_Errors(t *testing.T) {
+	require := require.New(t)
+	row := sql.NewMemoryRow("foo")
+	msg := NewGetField(0, sql.String, "message")
+
+	_, err := NewLike(msg, NewLiteral(`foo\`, sql.String), nil).Eval(row)
+	require.NotNil(err)
+
+	_, err = NewLike(msg, NewLiteral("foo", sql.String), NewLiteral("ab", sql.String)).Eval(row)
+	require.NotNil(err)
+
+	require.NotNil(NewLike(NewGetField(0, sql.Integer, "n"), msg, nil).CheckTypes())
+}
+
+func TestRegexp(t *testing.T) {
+	require := require.New(t)
+	msg := NewGetField(0, sql.String, "message")
+	pattern := NewGetField(1, sql.String, "pattern")
+
+	e := NewRegexp(msg, NewLiteral(`(?i)\brevert\b`, sql.String))
+	require.NotNil(e.cache)
+	require.Equal(true, eval(t, e, sql.NewMemoryRow("Revert foo", nil)))
+	require.Equal(false, eval(t, e, sql.NewMemoryRow("reverted foo", nil)))
+	require.Nil(eval(t, e, sql.NewMemoryRow(nil, nil)))
+
+	e = NewRegexp(msg, pattern)
+	require.Nil(e.cache)
+	require.Equal(true, eval(t, e, sql.NewMemoryRow("JIRA-12", `[A-Z]+-\d+`)))
+	require.Equal(false, eval(t, e, sql.NewMemoryRow("JIRA-12", `^\d`)))
+	require.Nil(eval(t, e, sql.NewMemoryRow("JIRA-12", nil)))
+
+	_, err := NewRegexp(msg, NewLiteral(`(`, sql.String)).Eval(sql.NewMemoryRow("foo"))
+	require.NotNil(err)
+}
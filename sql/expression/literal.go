@@ -20,10 +20,14 @@ func (p Literal) Type() sql.Type {
 	return p.fieldType
 }
 
-func (p Literal) Eval(row sql.Row) interface{} {
-	return p.value
+func (p Literal) Eval(row sql.Row) (interface{}, error) {
+	return p.value, nil
 }
 
 func (p Literal) Name() string {
 	return p.name
 }
+
+func (p Literal) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	return f(NewLiteral(p.value, p.fieldType))
+}
@@ -0,0 +1,49 @@
+package expression
+
+import (
+	"errors"
+
+	"github.com/mvader/gitql/sql"
+)
+
+// Star is the "*" used in place of an expression to refer to all columns,
+// such as in COUNT(*), or to all the columns of a relation if it is
+// qualified with its name.
+type Star struct {
+	table string
+}
+
+func NewStar() *Star {
+	return NewQualifiedStar("")
+}
+
+// NewQualifiedStar returns a Star that refers to all the columns of the
+// relation with the given name.
+func NewQualifiedStar(table string) *Star {
+	return &Star{table: table}
+}
+
+// Table returns the name of the relation that qualifies the star, or an
+// empty string if it is not qualified.
+func (s Star) Table() string {
+	return s.table
+}
+
+func (Star) Type() sql.Type {
+	return sql.String
+}
+
+func (s Star) Name() string {
+	if s.table == "" {
+		return "*"
+	}
+	return s.table + ".*"
+}
+
+func (Star) Eval(row sql.Row) (interface{}, error) {
+	return nil, errors.New("star can't be evaluated")
+}
+
+func (s Star) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	return f(NewQualifiedStar(s.table))
+}
@@ -0,0 +1,210 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mvader/gitql/sql"
+)
+
+// timeFormatVerbs are the layouts used to format each of the verbs that can
+// follow a % in the format of format_time.
+var timeFormatVerbs = map[byte]string{
+	'Y': "2006",
+	'y': "06",
+	'm': "01",
+	'd': "02",
+	'H': "15",
+	'M': "04",
+	'S': "05",
+	'b': "Jan",
+	'B': "January",
+	'a': "Mon",
+	'A': "Monday",
+	'z': "-0700",
+	'Z': "MST",
+}
+
+// toTime returns the time of the given timestamp in the given location, or
+// an error if the value can't be converted to a timestamp.
+func toTime(v interface{}, loc *time.Location) (time.Time, error) {
+	ts, err := sql.Timestamp.Convert(v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(ts.(int64), 0).In(loc), nil
+}
+
+// parseTimeZone returns the location of the given time zone, which is either
+// an UTC offset such as "+0200", as found in commits, or a name of the IANA
+// time zone database such as "Europe/Madrid".
+func parseTimeZone(zone string) (*time.Location, error) {
+	if t, err := time.Parse("-0700", zone); err == nil {
+		_, offset := t.Zone()
+		return time.FixedZone(zone, offset), nil
+	}
+
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", zone)
+	}
+	return loc, nil
+}
+
+// zonedFuncs returns two functions with the given name that evaluate
+// timestamps: one that evaluates them in UTC, and another one with an extra
+// last argument with the time zone to evaluate them in.
+func zonedFuncs(
+	name string,
+	typ sql.Type,
+	args []sql.Type,
+	fn func(loc *time.Location, args ...interface{}) (interface{}, error),
+) []Function {
+	zonedArgs := append(append([]sql.Type{}, args...), sql.String)
+	return []Function{
+		Func(name, typ, args, func(args ...interface{}) (interface{}, error) {
+			return fn(time.UTC, args...)
+		}),
+		Func(name, typ, zonedArgs, func(args ...interface{}) (interface{}, error) {
+			loc, err := parseTimeZone(args[len(args)-1].(string))
+			if err != nil {
+				return nil, err
+			}
+			return fn(loc, args[:len(args)-1]...)
+		}),
+	}
+}
+
+// timePartFuncs returns the functions that give a part of a timestamp as an
+// integer.
+func timePartFuncs(name string, part func(time.Time) int) []Function {
+	return zonedFuncs(name, sql.Integer, []sql.Type{sql.Timestamp},
+		func(loc *time.Location, args ...interface{}) (interface{}, error) {
+			t, err := toTime(args[0], loc)
+			if err != nil {
+				return nil, err
+			}
+			return int32(part(t)), nil
+		},
+	)
+}
+
+// truncateTime truncates the given time to the start of the given unit. Weeks
+// start on Monday.
+func truncateTime(unit string, t time.Time) (time.Time, error) {
+	y, m, d := t.Date()
+	loc := t.Location()
+	switch strings.ToLower(unit) {
+	case "second":
+		return t.Truncate(time.Second), nil
+	case "minute":
+		return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, loc), nil
+	case "hour":
+		return time.Date(y, m, d, t.Hour(), 0, 0, 0, loc), nil
+	case "day":
+		return time.Date(y, m, d, 0, 0, 0, 0, loc), nil
+	case "week":
+		return time.Date(y, m, d-isoWeekday(t)+1, 0, 0, 0, 0, loc), nil
+	case "month":
+		return time.Date(y, m, 1, 0, 0, 0, 0, loc), nil
+	case "quarter":
+		return time.Date(y, m-(m-1)%3, 1, 0, 0, 0, 0, loc), nil
+	case "year":
+		return time.Date(y, time.January, 1, 0, 0, 0, 0, loc), nil
+	}
+
+	return t, fmt.Errorf("unknown unit %q for date_trunc", unit)
+}
+
+// isoWeekday returns the day of the week of the given time, from 1 for
+// Monday to 7 for Sunday.
+func isoWeekday(t time.Time) int {
+	return (int(t.Weekday())+6)%7 + 1
+}
+
+// formatTime formats the given time with a format in which each verb
+// preceded by % is replaced by the corresponding part of the time, such as
+// "%Y-%m-%d".
+func formatTime(t time.Time, format string) (string, error) {
+	var buf []byte
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			buf = append(buf, format[i])
+			continue
+		}
+
+		i++
+		if i == len(format) {
+			return "", fmt.Errorf("missing verb at the end of format %q", format)
+		}
+
+		switch verb := format[i]; verb {
+		case '%':
+			buf = append(buf, '%')
+		case 'j':
+			buf = append(buf, fmt.Sprintf("%03d", t.YearDay())...)
+		case 'u':
+			buf = append(buf, fmt.Sprint(isoWeekday(t))...)
+		default:
+			layout, ok := timeFormatVerbs[verb]
+			if !ok {
+				return "", fmt.Errorf("unknown verb %%%c in format %q", verb, format)
+			}
+			buf = append(buf, t.Format(layout)...)
+		}
+	}
+
+	return string(buf), nil
+}
+
+func init() {
+	var fns []Function
+	fns = append(fns, timePartFuncs("year", func(t time.Time) int {
+		return t.Year()
+	})...)
+	fns = append(fns, timePartFuncs("month", func(t time.Time) int {
+		return int(t.Month())
+	})...)
+	fns = append(fns, timePartFuncs("day", func(t time.Time) int {
+		return t.Day()
+	})...)
+	fns = append(fns, timePartFuncs("hour", func(t time.Time) int {
+		return t.Hour()
+	})...)
+	fns = append(fns, timePartFuncs("day_of_week", isoWeekday)...)
+	fns = append(fns, zonedFuncs("date_trunc", sql.Timestamp, []sql.Type{sql.String, sql.Timestamp},
+		func(loc *time.Location, args ...interface{}) (interface{}, error) {
+			t, err := toTime(args[1], loc)
+			if err != nil {
+				return nil, err
+			}
+
+			t, err = truncateTime(args[0].(string), t)
+			if err != nil {
+				return nil, err
+			}
+			return t.Unix(), nil
+		},
+	)...)
+	fns = append(fns, zonedFuncs("format_time", sql.String, []sql.Type{sql.Timestamp, sql.String},
+		func(loc *time.Location, args ...interface{}) (interface{}, error) {
+			t, err := toTime(args[0], loc)
+			if err != nil {
+				return nil, err
+			}
+			return formatTime(t, args[1].(string))
+		},
+	)...)
+	// Timestamps are already seconds since the Unix epoch, so from_unixtime
+	// only changes the type of its argument.
+	fns = append(fns, Func("from_unixtime", sql.Timestamp, []sql.Type{sql.BigInteger},
+		func(args ...interface{}) (interface{}, error) {
+			return args[0], nil
+		},
+	))
+
+	if err := DefaultRegistry.Register(fns...); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,119 @@
+package expression
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mvader/gitql/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeFunctions(t *testing.T) {
+	require := require.New(t)
+	// Thursday, 2017-03-16 14:25:07 UTC
+	ts := time.Date(2017, time.March, 16, 14, 25, 7, 0, time.UTC).Unix()
+	row := sql.NewMemoryRow(ts)
+	col := NewGetField(0, sql.Timestamp, "t")
+	unix := func(s string) int64 {
+		v, err := sql.Timestamp.Convert(s)
+		require.Nil(err)
+		return v.(int64)
+	}
+
+	testCases := []struct {
+		name     string
+		args     []sql.Expression
+		expected interface{}
+	}{
+		{"year", []sql.Expression{col}, int32(2017)},
+		{"month", []sql.Expression{col}, int32(3)},
+		{"day", []sql.Expression{col}, int32(16)},
+		{"hour", []sql.Expression{col}, int32(14)},
+		{"day_of_week", []sql.Expression{col}, int32(4)},
+		{"from_unixtime", []sql.Expression{NewLiteral(ts, sql.BigInteger)}, ts},
+		{"date_trunc", []sql.Expression{NewLiteral("hour", sql.String), col}, unix("2017-03-16 14:00:00")},
+		{"date_trunc", []sql.Expression{NewLiteral("day", sql.String), col}, unix("2017-03-16")},
+		{"date_trunc", []sql.Expression{NewLiteral("WEEK", sql.String), col}, unix("2017-03-13")},
+		{"date_trunc", []sql.Expression{NewLiteral("quarter", sql.String), col}, unix("2017-01-01")},
+		{"date_trunc", []sql.Expression{NewLiteral("year", sql.String), col}, unix("2017-01-01")},
+		{"format_time", []sql.Expression{col, NewLiteral("%Y-%m-%d %H:%M:%S", sql.String)}, "2017-03-16 14:25:07"},
+		{"format_time", []sql.Expression{col, NewLiteral("%a %b %j %u 100%%", sql.String)}, "Thu Mar 075 4 100%"},
+	}
+
+	for _, tt := range testCases {
+		e, err := DefaultRegistry.Call(tt.name, tt.args...)
+		require.Nil(err, tt.name)
+		require.Nil(e.(*Call).CheckTypes(), tt.name)
+		require.Equal(tt.expected, eval(t, e, row), e.Name())
+	}
+
+	e, err := DefaultRegistry.Call("date_trunc", NewLiteral("century", sql.String), col)
+	require.Nil(err)
+	_, err = e.Eval(row)
+	require.EqualError(err, `unknown unit "century" for date_trunc`)
+
+	e, err = DefaultRegistry.Call("format_time", col, NewLiteral("%Q", sql.String))
+	require.Nil(err)
+	_, err = e.Eval(row)
+	require.EqualError(err, `unknown verb %Q in format "%Q"`)
+
+	e, err = DefaultRegistry.Call("year", NewLiteral("2020-01-01", sql.String))
+	require.Nil(err)
+	require.Equal(int32(2020), eval(t, e, row))
+
+	for _, name := range []string{"year", "day_of_week"} {
+		e, err = DefaultRegistry.Call(name, NewLiteral("yesterday", sql.String))
+		require.Nil(err)
+		_, err = e.Eval(row)
+		require.EqualError(err, `value "yesterday" can't be converted to timestamp`, name)
+	}
+
+	e, err = DefaultRegistry.Call("date_trunc", NewLiteral("day", sql.String), NewLiteral(true, sql.Boolean))
+	require.Nil(err)
+	_, err = e.Eval(row)
+	require.Equal(sql.ErrInvalidType, err)
+
+	e, err = DefaultRegistry.Call("format_time", NewLiteral(1e30, sql.Float), NewLiteral("%Y", sql.String))
+	require.Nil(err)
+	_, err = e.Eval(row)
+	require.EqualError(err, "value 1e+30 overflows int64")
+}
+
+func TestTimeFunctions_Zone(t *testing.T) {
+	require := require.New(t)
+	// 2017-03-16 23:25:07 UTC
+	ts := time.Date(2017, time.March, 16, 23, 25, 7, 0, time.UTC).Unix()
+	row := sql.NewMemoryRow(ts, "+0200")
+	col := NewGetField(0, sql.Timestamp, "t")
+	tz := NewGetField(1, sql.String, "tz")
+
+	testCases := []struct {
+		name     string
+		args     []sql.Expression
+		expected interface{}
+	}{
+		{"hour", []sql.Expression{col}, int32(23)},
+		{"hour", []sql.Expression{col, tz}, int32(1)},
+		{"hour", []sql.Expression{col, NewLiteral("-0930", sql.String)}, int32(13)},
+		{"hour", []sql.Expression{col, NewLiteral("UTC", sql.String)}, int32(23)},
+		{"day", []sql.Expression{col, tz}, int32(17)},
+		{"day_of_week", []sql.Expression{col, tz}, int32(5)},
+		{"format_time", []sql.Expression{col, NewLiteral("%d %H:%M %z", sql.String), tz}, "17 01:25 +0200"},
+		{"date_trunc", []sql.Expression{NewLiteral("day", sql.String), col, tz}, ts - 23*3600 - 25*60 - 7 + 22*3600},
+	}
+
+	for _, tt := range testCases {
+		e, err := DefaultRegistry.Call(tt.name, tt.args...)
+		require.Nil(err, tt.name)
+		require.Nil(e.(*Call).CheckTypes(), tt.name)
+		require.Equal(tt.expected, eval(t, e, row), e.Name())
+	}
+
+	e, err := DefaultRegistry.Call("hour", col, NewLiteral("Nowhere/Atlantis", sql.String))
+	require.Nil(err)
+	_, err = e.Eval(row)
+	require.EqualError(err, `unknown time zone "Nowhere/Atlantis"`)
+
+	_, err = DefaultRegistry.Call("hour")
+	require.EqualError(err, `function "hour" expects 1 or 2 arguments, 0 received`)
+}
@@ -0,0 +1,47 @@
+package plan
+
+import "github.com/mvader/gitql/sql"
+
+// CrossJoin is a node that combines every row of its left child with every
+// row of its right child.
+type CrossJoin struct {
+	left  sql.Node
+	right sql.Node
+}
+
+func NewCrossJoin(left sql.Node, right sql.Node) *CrossJoin {
+	return &CrossJoin{
+		left:  left,
+		right: right,
+	}
+}
+
+func (p *CrossJoin) Schema() sql.Schema {
+	return append(append(sql.Schema{}, p.left.Schema()...), p.right.Schema()...)
+}
+
+func (p *CrossJoin) Children() []sql.Node {
+	return []sql.Node{p.left, p.right}
+}
+
+func (p *CrossJoin) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	left, err := p.left.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := p.right.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewCrossJoin(left, right))
+}
+
+func (p *CrossJoin) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	return p, nil
+}
+
+func (p *CrossJoin) RowIter() (sql.RowIter, error) {
+	return newJoinIter(p.left, p.right, nil, false)
+}
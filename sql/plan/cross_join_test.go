@@ -0,0 +1,49 @@
+package plan
+
+import (
+	"io"
+	"testing"
+
+	"github.com/mvader/gitql/mem"
+	"github.com/mvader/gitql/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrossJoin(t *testing.T) {
+	require := require.New(t)
+	left := mem.NewTable("left", sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+	})
+	left.Insert("a")
+	left.Insert("b")
+	right := mem.NewTable("right", sql.Schema{
+		sql.Field{Name: "col2", Type: sql.Integer},
+	})
+	right.Insert(int32(1))
+	right.Insert(int32(2))
+
+	j := NewCrossJoin(left, right)
+	require.Equal(2, len(j.Children()))
+	require.Equal(sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String, Source: "left"},
+		sql.Field{Name: "col2", Type: sql.Integer, Source: "right"},
+	}, j.Schema())
+
+	iter, err := j.RowIter()
+	require.Nil(err)
+
+	expected := []sql.Row{
+		sql.NewMemoryRow("a", int32(1)),
+		sql.NewMemoryRow("a", int32(2)),
+		sql.NewMemoryRow("b", int32(1)),
+		sql.NewMemoryRow("b", int32(2)),
+	}
+	for _, e := range expected {
+		row, err := iter.Next()
+		require.Nil(err)
+		require.Equal(e, row)
+	}
+
+	_, err = iter.Next()
+	require.Equal(io.EOF, err)
+}
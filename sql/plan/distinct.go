@@ -0,0 +1,146 @@
+package plan
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"reflect"
+
+	"github.com/mvader/gitql/sql"
+)
+
+// Distinct is a node that removes the duplicated rows of its child. Rows are
+// streamed as soon as they are seen for the first time, so only the rows
+// already returned are kept in memory.
+type Distinct struct {
+	child sql.Node
+}
+
+func NewDistinct(child sql.Node) *Distinct {
+	return &Distinct{
+		child: child,
+	}
+}
+
+func (d *Distinct) Schema() sql.Schema {
+	return d.child.Schema()
+}
+
+func (d *Distinct) Children() []sql.Node {
+	return []sql.Node{d.child}
+}
+
+func (d *Distinct) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	c, err := d.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewDistinct(c))
+}
+
+func (d *Distinct) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	return d, nil
+}
+
+func (d *Distinct) RowIter() (sql.RowIter, error) {
+	i, err := d.child.RowIter()
+	if err != nil {
+		return nil, err
+	}
+	return &distinctIter{
+		schema:    d.child.Schema(),
+		childIter: i,
+		seen:      newRowSet(),
+	}, nil
+}
+
+type distinctIter struct {
+	schema    sql.Schema
+	childIter sql.RowIter
+	seen      *rowSet
+}
+
+func (i *distinctIter) Next() (sql.Row, error) {
+	for {
+		row, err := i.childIter.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if i.seen.add(i.schema, row.Fields()) {
+			return row, nil
+		}
+	}
+}
+
+// rowSet is a set of rows whose values are hashed and compared according to
+// the types of their schema.
+type rowSet struct {
+	buckets map[uint64][][]interface{}
+}
+
+func newRowSet() *rowSet {
+	return &rowSet{buckets: map[uint64][][]interface{}{}}
+}
+
+// add adds the given values to the set, returning false if they were
+// already in it.
+func (s *rowSet) add(schema sql.Schema, values []interface{}) bool {
+	h := hashValues(schema, values)
+	for _, other := range s.buckets[h] {
+		if equalValues(schema, values, other) {
+			return false
+		}
+	}
+
+	s.buckets[h] = append(s.buckets[h], values)
+	return true
+}
+
+func hashValues(schema sql.Schema, values []interface{}) uint64 {
+	h := fnv.New64a()
+	for i, v := range values {
+		hashValue(h, schema[i].Type, v)
+		// separator, so that adjacent values can't be mistaken for others
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+func hashValue(h hash.Hash64, t sql.Type, v interface{}) {
+	if v == nil {
+		h.Write([]byte{1})
+		return
+	}
+
+	switch t.InternalType() {
+	case reflect.String:
+		h.Write([]byte(v.(string)))
+	case reflect.Int32:
+		binary.Write(h, binary.LittleEndian, v.(int32))
+	case reflect.Int64:
+		binary.Write(h, binary.LittleEndian, v.(int64))
+	case reflect.Bool:
+		binary.Write(h, binary.LittleEndian, v.(bool))
+	default:
+		fmt.Fprintf(h, "%v", v)
+	}
+}
+
+func equalValues(schema sql.Schema, a, b []interface{}) bool {
+	for i, f := range schema {
+		if a[i] == nil || b[i] == nil {
+			if a[i] != b[i] {
+				return false
+			}
+			continue
+		}
+
+		if f.Type.Compare(a[i], b[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
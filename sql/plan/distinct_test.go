@@ -0,0 +1,59 @@
+package plan
+
+import (
+	"io"
+	"testing"
+
+	"github.com/mvader/gitql/mem"
+	"github.com/mvader/gitql/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistinct(t *testing.T) {
+	require := require.New(t)
+	childSchema := sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+		sql.Field{Name: "col2", Type: sql.Integer},
+	}
+	child := mem.NewTable("test", childSchema)
+	child.Insert("a", int32(1))
+	child.Insert("b", int32(1))
+	child.Insert("a", int32(1))
+	child.Insert("a", int32(2))
+	child.Insert("b", int32(1))
+
+	d := NewDistinct(child)
+	require.Equal(1, len(d.Children()))
+	require.Equal(child.Schema(), d.Schema())
+
+	iter, err := d.RowIter()
+	require.Nil(err)
+
+	expected := []sql.Row{
+		sql.NewMemoryRow("a", int32(1)),
+		sql.NewMemoryRow("b", int32(1)),
+		sql.NewMemoryRow("a", int32(2)),
+	}
+	for _, e := range expected {
+		row, err := iter.Next()
+		require.Nil(err)
+		require.Equal(e, row)
+	}
+
+	row, err := iter.Next()
+	require.Equal(io.EOF, err)
+	require.Nil(row)
+}
+
+func TestRowSet_Collisions(t *testing.T) {
+	require := require.New(t)
+	schema := sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+		sql.Field{Name: "col2", Type: sql.String},
+	}
+
+	s := newRowSet()
+	require.True(s.add(schema, []interface{}{"ab", "c"}))
+	require.True(s.add(schema, []interface{}{"a", "bc"}))
+	require.False(s.add(schema, []interface{}{"ab", "c"}))
+}
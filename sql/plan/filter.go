@@ -1,7 +1,13 @@
 package plan
 
-import "github.com/mvader/gitql/sql"
+import (
+	"fmt"
 
+	"github.com/mvader/gitql/sql"
+)
+
+// Filter is a node that only returns the rows of its child for which the
+// expression is true. Rows for which it is false or NULL are skipped.
 type Filter struct {
 	expression sql.Expression
 	child      sql.Node
@@ -14,10 +20,37 @@ func NewFilter(expression sql.Expression, child sql.Node) *Filter {
 	}
 }
 
+func (p *Filter) Schema() sql.Schema {
+	return p.child.Schema()
+}
+
 func (p *Filter) Children() []sql.Node {
 	return []sql.Node{p.child}
 }
 
+func (p *Filter) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	c, err := p.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewFilter(p.expression, c))
+}
+
+func (p *Filter) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	e, err := p.expression.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFilter(e, p.child), nil
+}
+
+// CheckTypes returns an error if the expression is not boolean.
+func (p *Filter) CheckTypes() error {
+	return checkCondition("filter", p.expression)
+}
+
 func (p *Filter) RowIter() (sql.RowIter, error) {
 	i, err := p.child.RowIter()
 	if err != nil {
@@ -34,11 +67,29 @@ type filterIter struct {
 func (i *filterIter) Next() (sql.Row, error) {
 	for {
 		row, err := i.childIter.Next()
+		if err != nil {
+			return nil, err
+		}
 
+		v, err := i.f.expression.Eval(row)
 		if err != nil {
 			return nil, err
-		} else if i.f.expression.Eval(row) != false {
+		}
+
+		if v == true {
 			return row, nil
 		}
 	}
 }
+
+// checkCondition returns an error if the given condition of a node of the
+// given kind is not boolean. NULL is accepted, as it is never true.
+func checkCondition(kind string, cond sql.Expression) error {
+	if t := cond.Type(); t != sql.Boolean && t != sql.Null {
+		return fmt.Errorf(
+			"%s condition %q must be %s, %s received",
+			kind, cond.Name(), sql.Boolean.Name(), t.Name(),
+		)
+	}
+	return nil
+}
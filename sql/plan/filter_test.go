@@ -1,6 +1,7 @@
 package plan
 
 import (
+	"io"
 	"testing"
 
 	"github.com/mvader/gitql/mem"
@@ -12,10 +13,10 @@ import (
 func TestFilter(t *testing.T) {
 	assert := assert.New(t)
 	childSchema := sql.Schema{
-		sql.Field{"col1", sql.String},
-		sql.Field{"col2", sql.String},
-		sql.Field{"col3", sql.Integer},
-		sql.Field{"col4", sql.BigInteger},
+		sql.Field{Name: "col1", Type: sql.String},
+		sql.Field{Name: "col2", Type: sql.String},
+		sql.Field{Name: "col3", Type: sql.Integer},
+		sql.Field{Name: "col4", Type: sql.BigInteger},
 	}
 	child := mem.NewTable("test", childSchema)
 	err := child.Insert("col1_1", "col2_1", int32(1111), int64(2222))
@@ -47,9 +48,9 @@ func TestFilter(t *testing.T) {
 	assert.Nil(row)
 
 	f = NewFilter(expression.NewEquals(
-		expression.NewGetField(2, sql.String, "col3"),
+		expression.NewGetField(2, sql.Integer, "col3"),
 		expression.NewLiteral(int32(1111),
-			sql.String)), child)
+			sql.Integer)), child)
 
 	iter, err = f.RowIter()
 	assert.Nil(err)
@@ -63,7 +64,7 @@ func TestFilter(t *testing.T) {
 	assert.Equal(int64(2222), row.Fields()[3])
 
 	f = NewFilter(expression.NewEquals(
-		expression.NewGetField(3, sql.String, "col4"),
+		expression.NewGetField(3, sql.BigInteger, "col4"),
 		expression.NewLiteral(int64(4444), sql.BigInteger)),
 		child)
 
@@ -78,3 +79,27 @@ func TestFilter(t *testing.T) {
 	assert.Equal(int32(3333), row.Fields()[2])
 	assert.Equal(int64(4444), row.Fields()[3])
 }
+
+func TestFilter_Null(t *testing.T) {
+	assert := assert.New(t)
+	child := mem.NewTable("test", sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String, Nullable: true},
+	})
+	assert.Nil(child.Insert("a"))
+	assert.Nil(child.Insert(nil))
+
+	f := NewFilter(expression.NewNotEquals(
+		expression.NewGetField(0, sql.String, "col1"),
+		expression.NewLiteral("b", sql.String),
+	), child)
+
+	iter, err := f.RowIter()
+	assert.Nil(err)
+
+	row, err := iter.Next()
+	assert.Nil(err)
+	assert.Equal(sql.NewMemoryRow("a"), row)
+
+	_, err = iter.Next()
+	assert.Equal(io.EOF, err)
+}
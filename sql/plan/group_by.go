@@ -0,0 +1,267 @@
+package plan
+
+import (
+	"io"
+
+	"github.com/mvader/gitql/sql"
+	"github.com/mvader/gitql/sql/expression"
+)
+
+// GroupBy is a node that groups the rows of its child by the values of the
+// grouping expressions and returns a row per group with the values of the
+// aggregate expressions. Aggregate expressions may combine aggregations with
+// any other expression; those outside of an aggregation are evaluated over
+// the first row of the group.
+type GroupBy struct {
+	aggregate []sql.Expression
+	grouping  []sql.Expression
+	child     sql.Node
+}
+
+func NewGroupBy(
+	aggregate []sql.Expression,
+	grouping []sql.Expression,
+	child sql.Node,
+) *GroupBy {
+	return &GroupBy{
+		aggregate: aggregate,
+		grouping:  grouping,
+		child:     child,
+	}
+}
+
+// Schema returns a field for each aggregate expression. The fields of the
+// expressions that are just fields of the child keep their source.
+func (p *GroupBy) Schema() sql.Schema {
+	schema := sql.Schema{}
+	for _, e := range p.aggregate {
+		f := sql.Field{Name: e.Name(), Type: e.Type()}
+		if gf, ok := e.(*expression.GetField); ok {
+			f.Source = gf.Table()
+		}
+		schema = append(schema, f)
+	}
+	return schema
+}
+
+func (p *GroupBy) Children() []sql.Node {
+	return []sql.Node{p.child}
+}
+
+func (p *GroupBy) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	c, err := p.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewGroupBy(p.aggregate, p.grouping, c))
+}
+
+func (p *GroupBy) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	aggregate, err := transformExpressions(p.aggregate, f)
+	if err != nil {
+		return nil, err
+	}
+
+	grouping, err := transformExpressions(p.grouping, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGroupBy(aggregate, grouping, p.child), nil
+}
+
+func (p *GroupBy) RowIter() (sql.RowIter, error) {
+	i, err := p.child.RowIter()
+	if err != nil {
+		return nil, err
+	}
+	return &groupByIter{p: p, childIter: i}, nil
+}
+
+type groupByIter struct {
+	p         *GroupBy
+	childIter sql.RowIter
+	rows      []sql.Row
+	idx       int
+	done      bool
+}
+
+func (i *groupByIter) Next() (sql.Row, error) {
+	if !i.done {
+		if err := i.computeRows(); err != nil {
+			return nil, err
+		}
+		i.done = true
+	}
+
+	if i.idx >= len(i.rows) {
+		return nil, io.EOF
+	}
+
+	row := i.rows[i.idx]
+	i.idx++
+	return row, nil
+}
+
+func (i *groupByIter) computeRows() error {
+	var (
+		groups  []*group
+		buckets = map[uint64][]*group{}
+		schema  = groupingSchema(i.p.grouping)
+	)
+
+	for {
+		row, err := i.childIter.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		key, err := evalExpressions(i.p.grouping, row)
+		if err != nil {
+			return err
+		}
+
+		h := hashValues(schema, key)
+		var g *group
+		for _, other := range buckets[h] {
+			if equalValues(schema, key, other.key) {
+				g = other
+				break
+			}
+		}
+
+		if g == nil {
+			g = newGroup(i.p.aggregate, key, row)
+			buckets[h] = append(buckets[h], g)
+			groups = append(groups, g)
+		}
+
+		if err := g.update(row); err != nil {
+			return err
+		}
+	}
+
+	// without grouping expressions there is always a group, even if the
+	// child has no rows
+	if len(groups) == 0 && len(i.p.grouping) == 0 {
+		empty := make([]interface{}, len(i.p.child.Schema()))
+		groups = append(groups, newGroup(i.p.aggregate, nil, sql.NewMemoryRow(empty...)))
+	}
+
+	for _, g := range groups {
+		row, err := g.eval(i.p.aggregate)
+		if err != nil {
+			return err
+		}
+		i.rows = append(i.rows, row)
+	}
+
+	return nil
+}
+
+type group struct {
+	key   []interface{}
+	first sql.Row
+	// buffers holds the buffers for the aggregations of each aggregate
+	// expression, in the order in which TransformUp visits them.
+	buffers [][]sql.AggregationBuffer
+}
+
+func newGroup(aggregate []sql.Expression, key []interface{}, first sql.Row) *group {
+	g := &group{key: key, first: first}
+	for _, e := range aggregate {
+		var buffers []sql.AggregationBuffer
+		for _, a := range findAggregations(e) {
+			buffers = append(buffers, a.NewBuffer())
+		}
+		g.buffers = append(g.buffers, buffers)
+	}
+	return g
+}
+
+func (g *group) update(row sql.Row) error {
+	for _, buffers := range g.buffers {
+		for _, b := range buffers {
+			if err := b.Update(row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *group) eval(aggregate []sql.Expression) (sql.Row, error) {
+	var fields []interface{}
+	for i, e := range aggregate {
+		var values []interface{}
+		for _, b := range g.buffers[i] {
+			v, err := b.Eval()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+
+		e, err := replaceAggregations(e, values)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := e.Eval(g.first)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, v)
+	}
+	return sql.NewMemoryRow(fields...), nil
+}
+
+func findAggregations(e sql.Expression) []sql.Aggregation {
+	var aggregations []sql.Aggregation
+	e.TransformUp(func(e sql.Expression) (sql.Expression, error) {
+		if a, ok := e.(sql.Aggregation); ok {
+			aggregations = append(aggregations, a)
+		}
+		return e, nil
+	})
+	return aggregations
+}
+
+// replaceAggregations replaces the aggregations in the given expression with
+// literals holding the given values, in the order of findAggregations.
+func replaceAggregations(e sql.Expression, values []interface{}) (sql.Expression, error) {
+	var i int
+	return e.TransformUp(func(e sql.Expression) (sql.Expression, error) {
+		if _, ok := e.(sql.Aggregation); !ok {
+			return e, nil
+		}
+
+		v := values[i]
+		i++
+		return expression.NewLiteral(v, e.Type()), nil
+	})
+}
+
+func groupingSchema(grouping []sql.Expression) sql.Schema {
+	var schema sql.Schema
+	for _, e := range grouping {
+		schema = append(schema, sql.Field{Name: e.Name(), Type: e.Type()})
+	}
+	return schema
+}
+
+func evalExpressions(exprs []sql.Expression, row sql.Row) ([]interface{}, error) {
+	var values []interface{}
+	for _, e := range exprs {
+		v, err := e.Eval(row)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
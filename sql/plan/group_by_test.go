@@ -0,0 +1,83 @@
+package plan
+
+import (
+	"io"
+	"testing"
+
+	"github.com/mvader/gitql/mem"
+	"github.com/mvader/gitql/sql"
+	"github.com/mvader/gitql/sql/expression"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupBy(t *testing.T) {
+	require := require.New(t)
+	childSchema := sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+		sql.Field{Name: "col2", Type: sql.Integer},
+	}
+	child := mem.NewTable("test", childSchema)
+	child.Insert("a", int32(1))
+	child.Insert("b", int32(3))
+	child.Insert("a", int32(5))
+	child.Insert("b", int32(7))
+	child.Insert("c", int32(9))
+
+	col1 := expression.NewGetField(0, sql.String, "col1")
+	col2 := expression.NewGetField(1, sql.Integer, "col2")
+	p := NewGroupBy(
+		[]sql.Expression{
+			col1,
+			expression.NewCount(expression.NewStar()),
+			expression.NewSum(col2),
+			expression.NewMax(col2),
+		},
+		[]sql.Expression{col1},
+		child,
+	)
+	require.Equal(1, len(p.Children()))
+	require.Equal(sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+		sql.Field{Name: "count(*)", Type: sql.BigInteger},
+		sql.Field{Name: "sum(col2)", Type: sql.BigInteger},
+		sql.Field{Name: "max(col2)", Type: sql.Integer},
+	}, p.Schema())
+
+	iter, err := p.RowIter()
+	require.Nil(err)
+
+	expected := []sql.Row{
+		sql.NewMemoryRow("a", int64(2), int64(6), int32(5)),
+		sql.NewMemoryRow("b", int64(2), int64(10), int32(7)),
+		sql.NewMemoryRow("c", int64(1), int64(9), int32(9)),
+	}
+	for _, e := range expected {
+		row, err := iter.Next()
+		require.Nil(err)
+		require.Equal(e, row)
+	}
+
+	_, err = iter.Next()
+	require.Equal(io.EOF, err)
+}
+
+func TestGroupBy_NoGrouping(t *testing.T) {
+	require := require.New(t)
+	child := mem.NewTable("test", sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+	})
+
+	p := NewGroupBy(
+		[]sql.Expression{expression.NewCount(expression.NewStar())},
+		nil,
+		child,
+	)
+
+	iter, err := p.RowIter()
+	require.Nil(err)
+	row, err := iter.Next()
+	require.Nil(err)
+	require.Equal(sql.NewMemoryRow(int64(0)), row)
+	_, err = iter.Next()
+	require.Equal(io.EOF, err)
+}
@@ -0,0 +1,363 @@
+package plan
+
+import (
+	"io"
+
+	"github.com/mvader/gitql/sql"
+	"github.com/mvader/gitql/sql/expression"
+)
+
+// InnerJoin is a node that combines the rows of its left and right children
+// for which the condition is true. The condition is evaluated over a row
+// with the fields of the left child followed by the fields of the right one.
+type InnerJoin struct {
+	left  sql.Node
+	right sql.Node
+	cond  sql.Expression
+}
+
+func NewInnerJoin(left sql.Node, right sql.Node, cond sql.Expression) *InnerJoin {
+	return &InnerJoin{
+		left:  left,
+		right: right,
+		cond:  cond,
+	}
+}
+
+func (p *InnerJoin) Schema() sql.Schema {
+	return append(append(sql.Schema{}, p.left.Schema()...), p.right.Schema()...)
+}
+
+func (p *InnerJoin) Children() []sql.Node {
+	return []sql.Node{p.left, p.right}
+}
+
+func (p *InnerJoin) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	left, err := p.left.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := p.right.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewInnerJoin(left, right, p.cond))
+}
+
+func (p *InnerJoin) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	cond, err := p.cond.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewInnerJoin(p.left, p.right, cond), nil
+}
+
+// CheckTypes returns an error if the condition is not boolean.
+func (p *InnerJoin) CheckTypes() error {
+	return checkCondition("join", p.cond)
+}
+
+func (p *InnerJoin) RowIter() (sql.RowIter, error) {
+	return newJoinIter(p.left, p.right, p.cond, false)
+}
+
+// LeftJoin is a node that behaves like InnerJoin, but also returns the rows
+// of its left child that don't match any row of the right child, with NULL
+// in place of the fields of the right child.
+type LeftJoin struct {
+	left  sql.Node
+	right sql.Node
+	cond  sql.Expression
+}
+
+func NewLeftJoin(left sql.Node, right sql.Node, cond sql.Expression) *LeftJoin {
+	return &LeftJoin{
+		left:  left,
+		right: right,
+		cond:  cond,
+	}
+}
+
+// Schema returns the fields of both children. The fields of the right child
+// are nullable, as they are NULL for the rows of the left child without a
+// match.
+func (p *LeftJoin) Schema() sql.Schema {
+	schema := append(sql.Schema{}, p.left.Schema()...)
+	for _, f := range p.right.Schema() {
+		f.Nullable = true
+		schema = append(schema, f)
+	}
+	return schema
+}
+
+func (p *LeftJoin) Children() []sql.Node {
+	return []sql.Node{p.left, p.right}
+}
+
+func (p *LeftJoin) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	left, err := p.left.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := p.right.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewLeftJoin(left, right, p.cond))
+}
+
+func (p *LeftJoin) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	cond, err := p.cond.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLeftJoin(p.left, p.right, cond), nil
+}
+
+// CheckTypes returns an error if the condition is not boolean.
+func (p *LeftJoin) CheckTypes() error {
+	return checkCondition("join", p.cond)
+}
+
+func (p *LeftJoin) RowIter() (sql.RowIter, error) {
+	return newJoinIter(p.left, p.right, p.cond, true)
+}
+
+// joinIter combines each row of the left child with the rows of the right
+// child that satisfy the condition, if any. The rows of the right child are
+// read only once and kept in memory. When the condition is an equality
+// between an expression over the left row and another over the right row,
+// the right rows are indexed by the value of the latter, so that each left
+// row is only checked against the right rows with the same value.
+type joinIter struct {
+	leftIter  sql.RowIter
+	right     sql.Node
+	rightSize int
+	cond      sql.Expression
+	outer     bool
+	keys      *joinKeys
+
+	loaded    bool
+	rightRows []sql.Row
+	index     map[uint64][]sql.Row
+
+	leftRow    sql.Row
+	candidates []sql.Row
+	matched    bool
+}
+
+func newJoinIter(
+	left sql.Node,
+	right sql.Node,
+	cond sql.Expression,
+	outer bool,
+) (*joinIter, error) {
+	leftIter, err := left.RowIter()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys *joinKeys
+	if cond != nil {
+		keys = equalityKeys(cond, len(left.Schema()))
+	}
+
+	return &joinIter{
+		leftIter:  leftIter,
+		right:     right,
+		rightSize: len(right.Schema()),
+		cond:      cond,
+		outer:     outer,
+		keys:      keys,
+	}, nil
+}
+
+func (i *joinIter) Next() (sql.Row, error) {
+	if !i.loaded {
+		if err := i.loadRight(); err != nil {
+			return nil, err
+		}
+		i.loaded = true
+	}
+
+	for {
+		if i.leftRow == nil {
+			row, err := i.leftIter.Next()
+			if err != nil {
+				return nil, err
+			}
+
+			candidates, err := i.candidatesFor(row)
+			if err != nil {
+				return nil, err
+			}
+
+			i.leftRow = row
+			i.candidates = candidates
+			i.matched = false
+		}
+
+		if len(i.candidates) == 0 {
+			left := i.leftRow
+			i.leftRow = nil
+			if i.outer && !i.matched {
+				return joinRows(left, make([]interface{}, i.rightSize)), nil
+			}
+			continue
+		}
+
+		right := i.candidates[0]
+		i.candidates = i.candidates[1:]
+		row := joinRows(i.leftRow, right.Fields())
+		if i.cond != nil {
+			v, err := i.cond.Eval(row)
+			if err != nil {
+				return nil, err
+			}
+
+			if v != true {
+				continue
+			}
+		}
+
+		i.matched = true
+		return row, nil
+	}
+}
+
+func (i *joinIter) loadRight() error {
+	iter, err := i.right.RowIter()
+	if err != nil {
+		return err
+	}
+
+	if i.keys != nil {
+		i.index = map[uint64][]sql.Row{}
+	}
+
+	for {
+		row, err := iter.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if i.keys == nil {
+			i.rightRows = append(i.rightRows, row)
+			continue
+		}
+
+		h, err := i.keys.hash(i.keys.right, row)
+		if err != nil {
+			return err
+		}
+		i.index[h] = append(i.index[h], row)
+	}
+}
+
+func (i *joinIter) candidatesFor(left sql.Row) ([]sql.Row, error) {
+	if i.keys == nil {
+		return i.rightRows, nil
+	}
+
+	h, err := i.keys.hash(i.keys.left, left)
+	if err != nil {
+		return nil, err
+	}
+	return i.index[h], nil
+}
+
+func joinRows(left sql.Row, right []interface{}) sql.Row {
+	fields := append([]interface{}{}, left.Fields()...)
+	return sql.NewMemoryRow(append(fields, right...)...)
+}
+
+// joinKeys are the two sides of an equality join condition. The left one is
+// evaluated over the rows of the left child and the right one over the rows
+// of the right child.
+type joinKeys struct {
+	left  sql.Expression
+	right sql.Expression
+}
+
+func (k *joinKeys) hash(e sql.Expression, row sql.Row) (uint64, error) {
+	v, err := e.Eval(row)
+	if err != nil {
+		return 0, err
+	}
+
+	schema := sql.Schema{sql.Field{Name: e.Name(), Type: e.Type()}}
+	return hashValues(schema, []interface{}{v}), nil
+}
+
+// equalityKeys returns the keys of the given join condition if it is an
+// equality between an expression that only uses fields of the left row and
+// another one that only uses fields of the right row, both of the same type.
+// The fields of the right key are rebased to be evaluated over right rows.
+func equalityKeys(cond sql.Expression, leftSize int) *joinKeys {
+	eq, ok := cond.(*expression.Equals)
+	if !ok || eq.Left().Type() != eq.Right().Type() {
+		return nil
+	}
+
+	l, r := eq.Left(), eq.Right()
+	if isLeftOnly(l, leftSize) && isRightOnly(r, leftSize) {
+		return &joinKeys{left: l, right: rebaseFields(r, leftSize)}
+	}
+
+	if isLeftOnly(r, leftSize) && isRightOnly(l, leftSize) {
+		return &joinKeys{left: r, right: rebaseFields(l, leftSize)}
+	}
+
+	return nil
+}
+
+func fieldIndexes(e sql.Expression) []int {
+	var indexes []int
+	e.TransformUp(func(e sql.Expression) (sql.Expression, error) {
+		if f, ok := e.(*expression.GetField); ok {
+			indexes = append(indexes, f.Index())
+		}
+		return e, nil
+	})
+	return indexes
+}
+
+func isLeftOnly(e sql.Expression, leftSize int) bool {
+	indexes := fieldIndexes(e)
+	for _, idx := range indexes {
+		if idx >= leftSize {
+			return false
+		}
+	}
+	return len(indexes) > 0
+}
+
+func isRightOnly(e sql.Expression, leftSize int) bool {
+	indexes := fieldIndexes(e)
+	for _, idx := range indexes {
+		if idx < leftSize {
+			return false
+		}
+	}
+	return len(indexes) > 0
+}
+
+func rebaseFields(e sql.Expression, offset int) sql.Expression {
+	e, _ = e.TransformUp(func(e sql.Expression) (sql.Expression, error) {
+		f, ok := e.(*expression.GetField)
+		if !ok {
+			return e, nil
+		}
+		return expression.NewGetFieldWithTable(f.Index()-offset, f.Table(), f.Type(), f.Name()), nil
+	})
+	return e
+}
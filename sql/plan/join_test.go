@@ -0,0 +1,133 @@
+package plan
+
+import (
+	"io"
+	"testing"
+
+	"github.com/mvader/gitql/mem"
+	"github.com/mvader/gitql/sql"
+	"github.com/mvader/gitql/sql/expression"
+	"github.com/stretchr/testify/require"
+)
+
+func joinTables() (*mem.Table, *mem.Table) {
+	left := mem.NewTable("left", sql.Schema{
+		sql.Field{Name: "name", Type: sql.String},
+		sql.Field{Name: "email", Type: sql.String},
+	})
+	left.Insert("alice", "alice@example.com")
+	left.Insert("bob", "bob@example.com")
+	left.Insert("carol", "carol@example.com")
+
+	right := mem.NewTable("right", sql.Schema{
+		sql.Field{Name: "author_email", Type: sql.String},
+		sql.Field{Name: "hash", Type: sql.String},
+	})
+	right.Insert("bob@example.com", "h1")
+	right.Insert("alice@example.com", "h2")
+	right.Insert("bob@example.com", "h3")
+
+	return left, right
+}
+
+func TestInnerJoin(t *testing.T) {
+	require := require.New(t)
+	left, right := joinTables()
+
+	conditions := []sql.Expression{
+		// hash join
+		expression.NewEquals(
+			expression.NewGetField(1, sql.String, "email"),
+			expression.NewGetField(2, sql.String, "author_email"),
+		),
+		// nested loop join
+		expression.NewEquals(
+			expression.NewEquals(
+				expression.NewGetField(1, sql.String, "email"),
+				expression.NewGetField(2, sql.String, "author_email"),
+			),
+			expression.NewLiteral(true, sql.Boolean),
+		),
+	}
+
+	for _, cond := range conditions {
+		j := NewInnerJoin(left, right, cond)
+		require.Equal(2, len(j.Children()))
+		require.Equal(4, len(j.Schema()))
+
+		iter, err := j.RowIter()
+		require.Nil(err)
+
+		expected := []sql.Row{
+			sql.NewMemoryRow("alice", "alice@example.com", "alice@example.com", "h2"),
+			sql.NewMemoryRow("bob", "bob@example.com", "bob@example.com", "h1"),
+			sql.NewMemoryRow("bob", "bob@example.com", "bob@example.com", "h3"),
+		}
+		for _, e := range expected {
+			row, err := iter.Next()
+			require.Nil(err)
+			require.Equal(e, row)
+		}
+
+		_, err = iter.Next()
+		require.Equal(io.EOF, err)
+	}
+}
+
+func TestLeftJoin(t *testing.T) {
+	require := require.New(t)
+	left, right := joinTables()
+
+	j := NewLeftJoin(left, right, expression.NewEquals(
+		expression.NewGetField(2, sql.String, "author_email"),
+		expression.NewGetField(1, sql.String, "email"),
+	))
+
+	require.Equal(sql.Schema{
+		sql.Field{Name: "name", Type: sql.String, Source: "left"},
+		sql.Field{Name: "email", Type: sql.String, Source: "left"},
+		sql.Field{Name: "author_email", Type: sql.String, Nullable: true, Source: "right"},
+		sql.Field{Name: "hash", Type: sql.String, Nullable: true, Source: "right"},
+	}, j.Schema())
+
+	iter, err := j.RowIter()
+	require.Nil(err)
+
+	expected := []sql.Row{
+		sql.NewMemoryRow("alice", "alice@example.com", "alice@example.com", "h2"),
+		sql.NewMemoryRow("bob", "bob@example.com", "bob@example.com", "h1"),
+		sql.NewMemoryRow("bob", "bob@example.com", "bob@example.com", "h3"),
+		sql.NewMemoryRow("carol", "carol@example.com", nil, nil),
+	}
+	for _, e := range expected {
+		row, err := iter.Next()
+		require.Nil(err)
+		require.Equal(e, row)
+	}
+
+	_, err = iter.Next()
+	require.Equal(io.EOF, err)
+}
+
+func TestEqualityKeys(t *testing.T) {
+	require := require.New(t)
+
+	keys := equalityKeys(expression.NewEquals(
+		expression.NewGetField(3, sql.String, "b"),
+		expression.NewGetField(0, sql.String, "a"),
+	), 2)
+	require.Equal(&joinKeys{
+		left:  expression.NewGetField(0, sql.String, "a"),
+		right: expression.NewGetField(1, sql.String, "b"),
+	}, keys)
+
+	require.Nil(equalityKeys(expression.NewEquals(
+		expression.NewGetField(0, sql.String, "a"),
+		expression.NewGetField(1, sql.String, "b"),
+	), 2))
+
+	require.Nil(equalityKeys(expression.NewEquals(
+		expression.NewGetField(0, sql.String, "a"),
+		expression.NewGetField(2, sql.Integer, "b"),
+	), 2))
+}
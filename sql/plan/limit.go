@@ -0,0 +1,71 @@
+package plan
+
+import (
+	"io"
+
+	"github.com/mvader/gitql/sql"
+)
+
+// Limit is a node that only returns the first size rows of its child.
+type Limit struct {
+	size  int64
+	child sql.Node
+}
+
+func NewLimit(size int64, child sql.Node) *Limit {
+	return &Limit{
+		size:  size,
+		child: child,
+	}
+}
+
+func (l *Limit) Schema() sql.Schema {
+	return l.child.Schema()
+}
+
+func (l *Limit) Children() []sql.Node {
+	return []sql.Node{l.child}
+}
+
+func (l *Limit) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	c, err := l.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewLimit(l.size, c))
+}
+
+func (l *Limit) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	return l, nil
+}
+
+func (l *Limit) RowIter() (sql.RowIter, error) {
+	i, err := l.child.RowIter()
+	if err != nil {
+		return nil, err
+	}
+	return &limitIter{l: l, childIter: i}, nil
+}
+
+type limitIter struct {
+	l         *Limit
+	childIter sql.RowIter
+	count     int64
+}
+
+func (i *limitIter) Next() (sql.Row, error) {
+	// once the limit is reached the child is not asked for more rows, so
+	// the rest of the relation is never read
+	if i.count >= i.l.size {
+		return nil, io.EOF
+	}
+
+	row, err := i.childIter.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	i.count++
+	return row, nil
+}
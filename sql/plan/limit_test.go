@@ -0,0 +1,59 @@
+package plan
+
+import (
+	"io"
+	"testing"
+
+	"github.com/mvader/gitql/mem"
+	"github.com/mvader/gitql/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimit(t *testing.T) {
+	require := require.New(t)
+	childSchema := sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+	}
+	child := mem.NewTable("test", childSchema)
+	child.Insert("a")
+	child.Insert("b")
+	child.Insert("c")
+
+	l := NewLimit(2, child)
+	require.Equal(1, len(l.Children()))
+	require.Equal(child.Schema(), l.Schema())
+
+	iter, err := l.RowIter()
+	require.Nil(err)
+	row, err := iter.Next()
+	require.Nil(err)
+	require.Equal("a", row.Fields()[0])
+	row, err = iter.Next()
+	require.Nil(err)
+	require.Equal("b", row.Fields()[0])
+	row, err = iter.Next()
+	require.Equal(io.EOF, err)
+	require.Nil(row)
+}
+
+func TestLimit_StopsReadingChild(t *testing.T) {
+	require := require.New(t)
+	child := &countingIter{}
+	iter := &limitIter{l: NewLimit(2, nil), childIter: child}
+
+	for i := 0; i < 5; i++ {
+		iter.Next()
+	}
+	require.Equal(2, child.calls)
+}
+
+// countingIter is an endless iterator that counts how many rows were
+// requested from it.
+type countingIter struct {
+	calls int
+}
+
+func (i *countingIter) Next() (sql.Row, error) {
+	i.calls++
+	return sql.NewMemoryRow(i.calls), nil
+}
@@ -0,0 +1,64 @@
+package plan
+
+import "github.com/mvader/gitql/sql"
+
+// Offset is a node that skips the first n rows of its child.
+type Offset struct {
+	n     int64
+	child sql.Node
+}
+
+func NewOffset(n int64, child sql.Node) *Offset {
+	return &Offset{
+		n:     n,
+		child: child,
+	}
+}
+
+func (o *Offset) Schema() sql.Schema {
+	return o.child.Schema()
+}
+
+func (o *Offset) Children() []sql.Node {
+	return []sql.Node{o.child}
+}
+
+func (o *Offset) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	c, err := o.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewOffset(o.n, c))
+}
+
+func (o *Offset) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	return o, nil
+}
+
+func (o *Offset) RowIter() (sql.RowIter, error) {
+	i, err := o.child.RowIter()
+	if err != nil {
+		return nil, err
+	}
+	return &offsetIter{o: o, childIter: i}, nil
+}
+
+type offsetIter struct {
+	o         *Offset
+	childIter sql.RowIter
+	skipped   bool
+}
+
+func (i *offsetIter) Next() (sql.Row, error) {
+	if !i.skipped {
+		for n := int64(0); n < i.o.n; n++ {
+			if _, err := i.childIter.Next(); err != nil {
+				return nil, err
+			}
+		}
+		i.skipped = true
+	}
+
+	return i.childIter.Next()
+}
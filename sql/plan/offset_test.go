@@ -0,0 +1,39 @@
+package plan
+
+import (
+	"io"
+	"testing"
+
+	"github.com/mvader/gitql/mem"
+	"github.com/mvader/gitql/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffset(t *testing.T) {
+	require := require.New(t)
+	childSchema := sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+	}
+	child := mem.NewTable("test", childSchema)
+	child.Insert("a")
+	child.Insert("b")
+	child.Insert("c")
+
+	o := NewOffset(2, child)
+	require.Equal(1, len(o.Children()))
+	require.Equal(child.Schema(), o.Schema())
+
+	iter, err := o.RowIter()
+	require.Nil(err)
+	row, err := iter.Next()
+	require.Nil(err)
+	require.Equal("c", row.Fields()[0])
+	row, err = iter.Next()
+	require.Equal(io.EOF, err)
+	require.Nil(row)
+
+	iter, err = NewOffset(5, child).RowIter()
+	require.Nil(err)
+	_, err = iter.Next()
+	require.Equal(io.EOF, err)
+}
@@ -1,7 +1,14 @@
 package plan
 
-import "github.com/mvader/gitql/sql"
+import (
+	"github.com/mvader/gitql/sql"
+	"github.com/mvader/gitql/sql/expression"
+)
 
+// Project is a node that returns the values of its expressions for each row
+// of its child. Its schema has a field for each expression, with the name
+// and type of the expression. Expressions that are just fields of the child
+// keep the nullability and source of the child's field.
 type Project struct {
 	expressions []sql.Expression
 	schema      sql.Schema
@@ -9,15 +16,15 @@ type Project struct {
 }
 
 func NewProject(expressions []sql.Expression, child sql.Node) *Project {
-	schema := sql.Schema{}
 	childSchema := child.Schema()
+	schema := sql.Schema{}
 	for _, expr := range expressions {
-		for _, field := range childSchema {
-			if expr.Name() == field.Name {
-				schema = append(schema, field)
-				break
-			}
+		f := sql.Field{Name: expr.Name(), Type: expr.Type()}
+		if gf, ok := expr.(*expression.GetField); ok && gf.Index() < len(childSchema) {
+			f.Nullable = childSchema[gf.Index()].Nullable
+			f.Source = childSchema[gf.Index()].Source
 		}
+		schema = append(schema, f)
 	}
 	return &Project{
 		expressions: expressions,
@@ -26,6 +33,11 @@ func NewProject(expressions []sql.Expression, child sql.Node) *Project {
 	}
 }
 
+// Expressions returns the expressions projected by the node.
+func (p *Project) Expressions() []sql.Expression {
+	return p.expressions
+}
+
 func (p *Project) Children() []sql.Node {
 	return []sql.Node{p.child}
 }
@@ -34,6 +46,24 @@ func (p *Project) Schema() sql.Schema {
 	return p.schema
 }
 
+func (p *Project) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	c, err := p.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewProject(p.expressions, c))
+}
+
+func (p *Project) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	exprs, err := transformExpressions(p.expressions, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewProject(exprs, p.child), nil
+}
+
 func (p *Project) RowIter() (sql.RowIter, error) {
 	i, err := p.child.RowIter()
 	if err != nil {
@@ -52,13 +82,32 @@ func (i *iter) Next() (sql.Row, error) {
 	if err != nil {
 		return nil, err
 	}
-	return filterRow(i.p.expressions, childRow), nil
+	return filterRow(i.p.expressions, childRow)
 }
 
-func filterRow(expressions []sql.Expression, row sql.Row) sql.Row {
+func filterRow(expressions []sql.Expression, row sql.Row) (sql.Row, error) {
 	fields := []interface{}{}
 	for _, expr := range expressions {
-		fields = append(fields, expr.Eval(row))
+		f, err := expr.Eval(row)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	return sql.NewMemoryRow(fields...), nil
+}
+
+func transformExpressions(
+	exprs []sql.Expression,
+	f sql.TransformExprFunc,
+) ([]sql.Expression, error) {
+	var result []sql.Expression
+	for _, e := range exprs {
+		te, err := e.TransformUp(f)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, te)
 	}
-	return sql.NewMemoryRow(fields...)
+	return result, nil
 }
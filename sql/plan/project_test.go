@@ -13,8 +13,8 @@ import (
 func TestProject(t *testing.T) {
 	require := require.New(t)
 	childSchema := sql.Schema{
-		sql.Field{"col1", sql.String},
-		sql.Field{"col2", sql.String},
+		sql.Field{Name: "col1", Type: sql.String},
+		sql.Field{Name: "col2", Type: sql.String},
 	}
 	child := mem.NewTable("test", childSchema)
 	child.Insert("col1_1", "col2_1")
@@ -22,7 +22,7 @@ func TestProject(t *testing.T) {
 	p := NewProject([]sql.Expression{expression.NewGetField(1, sql.String, "col2")}, child)
 	require.Equal(1, len(p.Children()))
 	schema := sql.Schema{
-		sql.Field{"col2", sql.String},
+		sql.Field{Name: "col2", Type: sql.String, Source: "test"},
 	}
 	require.Equal(schema, p.Schema())
 	iter, err := p.RowIter()
@@ -46,4 +46,31 @@ func TestProject(t *testing.T) {
 
 	p = NewProject(nil, child)
 	require.Equal(0, len(p.schema))
+
+	p = NewProject([]sql.Expression{
+		expression.NewAlias(
+			expression.NewEquals(
+				expression.NewGetField(0, sql.String, "col1"),
+				expression.NewLiteral("col1_2", sql.String),
+			),
+			"is_second",
+		),
+		expression.NewGetField(0, sql.String, "col1"),
+	}, child)
+	require.Equal(sql.Schema{
+		sql.Field{Name: "is_second", Type: sql.Boolean},
+		sql.Field{Name: "col1", Type: sql.String, Source: "test"},
+	}, p.Schema())
+
+	nullable := mem.NewTable("nullable", sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String, Nullable: true},
+	})
+	p = NewProject([]sql.Expression{
+		expression.NewGetFieldWithTable(0, "nullable", sql.String, "col1"),
+		expression.NewAlias(expression.NewGetField(0, sql.String, "col1"), "alias"),
+	}, nullable)
+	require.Equal(sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String, Nullable: true, Source: "nullable"},
+		sql.Field{Name: "alias", Type: sql.String},
+	}, p.Schema())
 }
@@ -1,7 +1,6 @@
 package plan
 
 import (
-	"fmt"
 	"io"
 	"sort"
 
@@ -9,9 +8,8 @@ import (
 )
 
 type Sort struct {
-	fieldIndexes []int
-	fieldTypes   []sql.Type
-	child        sql.Node
+	sortFields []SortField
+	child      sql.Node
 }
 
 type SortOrder byte
@@ -22,32 +20,14 @@ const (
 )
 
 type SortField struct {
-	Column string
-	Order  SortOrder
+	Expression sql.Expression
+	Order      SortOrder
 }
 
 func NewSort(sortFields []SortField, child sql.Node) *Sort {
-	indexes := []int{}
-	types := []sql.Type{}
-	childSchema := child.Schema()
-	for _, sortField := range sortFields {
-		found := false
-		for idx, field := range childSchema {
-			if field.Name == sortField.Column {
-				indexes = append(indexes, idx)
-				types = append(types, field.Type)
-				found = true
-				break
-			}
-		}
-		if found == false {
-			panic(fmt.Errorf("Field %s not found in child", sortField.Column))
-		}
-	}
 	return &Sort{
-		fieldIndexes: indexes,
-		fieldTypes:   types,
-		child:        child,
+		sortFields: sortFields,
+		child:      child,
 	}
 }
 
@@ -59,6 +39,28 @@ func (s *Sort) Schema() sql.Schema {
 	return s.child.Schema()
 }
 
+func (s *Sort) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	c, err := s.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewSort(s.sortFields, c))
+}
+
+func (s *Sort) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	var fields []SortField
+	for _, sf := range s.sortFields {
+		e, err := sf.Expression.TransformUp(f)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, SortField{Expression: e, Order: sf.Order})
+	}
+
+	return NewSort(fields, s.child), nil
+}
+
 func (s *Sort) RowIter() (sql.RowIter, error) {
 	i, err := s.child.RowIter()
 	if err != nil {
@@ -85,14 +87,12 @@ func newSortIter(s *Sort, child sql.RowIter) *sortIter {
 
 func (i *sortIter) Next() (sql.Row, error) {
 	if i.idx == -1 {
-		println("computing sorted rows")
 		err := i.computeSortedRows()
 		if err != nil {
 			return nil, err
 		}
 		i.idx = 0
 	}
-	println("sorted rows: ", i.sortedRows)
 	if i.idx >= len(i.sortedRows) {
 		return nil, io.EOF
 	}
@@ -103,6 +103,7 @@ func (i *sortIter) Next() (sql.Row, error) {
 
 func (i *sortIter) computeSortedRows() error {
 	rows := []sql.Row{}
+	keys := [][]interface{}{}
 	for {
 		childRow, err := i.childIter.Next()
 		if err == io.EOF {
@@ -111,21 +112,40 @@ func (i *sortIter) computeSortedRows() error {
 		if err != nil {
 			return err
 		}
+
+		key, err := sortKey(i.s.sortFields, childRow)
+		if err != nil {
+			return err
+		}
+
 		rows = append(rows, childRow)
+		keys = append(keys, key)
 	}
-	sort.Sort(&sorter{
-		indexes: i.s.fieldIndexes,
-		types:   i.s.fieldTypes,
-		rows:    rows,
+	sort.Stable(&sorter{
+		fields: i.s.sortFields,
+		rows:   rows,
+		keys:   keys,
 	})
 	i.sortedRows = rows
 	return nil
 }
 
+func sortKey(fields []SortField, row sql.Row) ([]interface{}, error) {
+	key := make([]interface{}, len(fields))
+	for i, f := range fields {
+		v, err := f.Expression.Eval(row)
+		if err != nil {
+			return nil, err
+		}
+		key[i] = v
+	}
+	return key, nil
+}
+
 type sorter struct {
-	indexes []int
-	types   []sql.Type
-	rows    []sql.Row
+	fields []SortField
+	rows   []sql.Row
+	keys   [][]interface{}
 }
 
 func (s *sorter) Len() int {
@@ -134,18 +154,36 @@ func (s *sorter) Len() int {
 
 func (s *sorter) Swap(i, j int) {
 	s.rows[i], s.rows[j] = s.rows[j], s.rows[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
 }
 
 func (s *sorter) Less(i, j int) bool {
-	a := s.rows[i].Fields()
-	b := s.rows[j].Fields()
-	for i, idx := range s.indexes {
-		typ := s.types[i]
-		av := a[idx]
-		bv := b[idx]
-		if typ.Compare(av, bv) == -1 {
-			return true
+	a := s.keys[i]
+	b := s.keys[j]
+	for idx, f := range s.fields {
+		cmp := compareValues(f.Expression.Type(), a[idx], b[idx])
+		if cmp == 0 {
+			continue
+		}
+
+		if f.Order == Descending {
+			return cmp > 0
 		}
+		return cmp < 0
 	}
 	return false
 }
+
+// compareValues compares two values of the given type. NULL is smaller than
+// any other value.
+func compareValues(t sql.Type, a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	return t.Compare(a, b)
+}
@@ -6,41 +6,84 @@ import (
 
 	"github.com/mvader/gitql/mem"
 	"github.com/mvader/gitql/sql"
+	"github.com/mvader/gitql/sql/expression"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestSort(t *testing.T) {
 	assert := assert.New(t)
 	childSchema := sql.Schema{
-		sql.Field{"col1", sql.String},
-		sql.Field{"col2", sql.Integer},
+		sql.Field{Name: "col1", Type: sql.String},
+		sql.Field{Name: "col2", Type: sql.Integer},
 	}
 	child := mem.NewTable("test", childSchema)
 	child.Insert("a", int32(3))
 	child.Insert("b", int32(3))
 	child.Insert("c", int32(1))
+	child.Insert("d", int32(2))
 	sf := []SortField{
-		{Column: "col2", Order: Ascending},
-		{Column: "col1", Order: Descending},
+		{Expression: expression.NewGetField(1, sql.Integer, "col2"), Order: Ascending},
+		{Expression: expression.NewGetField(0, sql.String, "col1"), Order: Descending},
 	}
 	s := NewSort(sf, child)
-	assert.Equal(childSchema, s.Schema())
+	assert.Equal(child.Schema(), s.Schema())
 	iter, err := s.RowIter()
 	assert.Nil(err)
 	assert.NotNil(iter)
+	for _, expected := range []string{"c", "d", "b", "a"} {
+		row, err := iter.Next()
+		assert.Nil(err)
+		assert.NotNil(row)
+		assert.Equal(expected, row.Fields()[0])
+	}
 	row, err := iter.Next()
+	assert.Equal(io.EOF, err)
+	assert.Nil(row)
+}
+
+func TestSort_Descending(t *testing.T) {
+	assert := assert.New(t)
+	childSchema := sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+		sql.Field{Name: "col2", Type: sql.Integer},
+	}
+	child := mem.NewTable("test", childSchema)
+	child.Insert("a", int32(1))
+	child.Insert("b", int32(3))
+	child.Insert("c", int32(2))
+	sf := []SortField{
+		{Expression: expression.NewGetField(1, sql.Integer, "col2"), Order: Descending},
+	}
+	iter, err := NewSort(sf, child).RowIter()
 	assert.Nil(err)
-	assert.NotNil(row)
-	assert.Equal("c", row.Fields()[0])
-	row, err = iter.Next()
-	assert.Nil(err)
-	assert.NotNil(row)
-	assert.Equal("a", row.Fields()[0])
-	row, err = iter.Next()
+	for _, expected := range []string{"b", "c", "a"} {
+		row, err := iter.Next()
+		assert.Nil(err)
+		assert.Equal(expected, row.Fields()[0])
+	}
+	_, err = iter.Next()
+	assert.Equal(io.EOF, err)
+}
+
+func TestSort_Null(t *testing.T) {
+	assert := assert.New(t)
+	child := mem.NewTable("test", sql.Schema{
+		sql.Field{Name: "col1", Type: sql.String},
+		sql.Field{Name: "col2", Type: sql.Integer, Nullable: true},
+	})
+	child.Insert("a", int32(1))
+	child.Insert("b", nil)
+	child.Insert("c", int32(2))
+	sf := []SortField{
+		{Expression: expression.NewGetField(1, sql.Integer, "col2"), Order: Ascending},
+	}
+	iter, err := NewSort(sf, child).RowIter()
 	assert.Nil(err)
-	assert.NotNil(row)
-	assert.Equal("b", row.Fields()[0])
-	row, err = iter.Next()
+	for _, expected := range []string{"b", "a", "c"} {
+		row, err := iter.Next()
+		assert.Nil(err)
+		assert.Equal(expected, row.Fields()[0])
+	}
+	_, err = iter.Next()
 	assert.Equal(io.EOF, err)
-	assert.Nil(row)
 }
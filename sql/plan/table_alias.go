@@ -0,0 +1,51 @@
+package plan
+
+import "github.com/mvader/gitql/sql"
+
+// TableAlias is a node that gives another name to a relation. It returns
+// the same rows as its child, and its fields have the alias as their source.
+type TableAlias struct {
+	name  string
+	child sql.Node
+}
+
+func NewTableAlias(name string, child sql.Node) *TableAlias {
+	return &TableAlias{
+		name:  name,
+		child: child,
+	}
+}
+
+func (p *TableAlias) Name() string {
+	return p.name
+}
+
+func (p *TableAlias) Schema() sql.Schema {
+	var schema sql.Schema
+	for _, f := range p.child.Schema() {
+		f.Source = p.name
+		schema = append(schema, f)
+	}
+	return schema
+}
+
+func (p *TableAlias) Children() []sql.Node {
+	return []sql.Node{p.child}
+}
+
+func (p *TableAlias) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	c, err := p.child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewTableAlias(p.name, c))
+}
+
+func (p *TableAlias) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	return p, nil
+}
+
+func (p *TableAlias) RowIter() (sql.RowIter, error) {
+	return p.child.RowIter()
+}
@@ -2,9 +2,11 @@ package sql
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Schema []Field
@@ -12,6 +14,12 @@ type Schema []Field
 type Field struct {
 	Name string
 	Type Type
+	// Nullable is true if the field may contain NULL values, which are
+	// represented by nil.
+	Nullable bool
+	// Source is the name of the relation the field comes from, if any. It
+	// is used to resolve columns qualified with a relation name.
+	Source string
 }
 
 type Type interface {
@@ -22,6 +30,18 @@ type Type interface {
 	Compare(interface{}, interface{}) int
 }
 
+// TypeByName returns the type with the given name, which is not case
+// sensitive.
+func TypeByName(name string) (Type, error) {
+	for _, t := range []Type{Integer, BigInteger, Float, Timestamp, String, Boolean} {
+		if strings.EqualFold(t.Name(), name) {
+			return t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown type %q", name)
+}
+
 var Integer = integerType{}
 
 type integerType struct{}
@@ -70,6 +90,33 @@ func (t bigIntegerType) Compare(a interface{}, b interface{}) int {
 	return compareInt64(a, b)
 }
 
+var Float = floatType{}
+
+type floatType struct{}
+
+func (t floatType) Name() string {
+	return "float"
+}
+
+func (t floatType) InternalType() reflect.Kind {
+	return reflect.Float64
+}
+
+func (t floatType) Check(v interface{}) bool {
+	return checkFloat64(v)
+}
+
+func (t floatType) Convert(v interface{}) (interface{}, error) {
+	return convertToFloat64(v)
+}
+
+func (t floatType) Compare(a interface{}, b interface{}) int {
+	return compareFloat64(a, b)
+}
+
+// Timestamp is a point in time, represented by the number of seconds since
+// the Unix epoch. Strings in any of the layouts of TimestampLayouts can be
+// converted to timestamps.
 var Timestamp = timestampType{}
 
 type timestampType struct{}
@@ -87,13 +134,15 @@ func (t timestampType) Check(v interface{}) bool {
 }
 
 func (t timestampType) Convert(v interface{}) (interface{}, error) {
-	return convertToInt64(v)
+	return convertToTimestamp(v)
 }
 
 func (t timestampType) Compare(a interface{}, b interface{}) int {
 	return compareInt64(a, b)
 }
 
+// String is a text. Numbers, booleans and times can be converted to strings,
+// the latter in the first layout of TimestampLayouts.
 var String = stringType{}
 
 type stringType struct{}
@@ -118,6 +167,8 @@ func (t stringType) Compare(a interface{}, b interface{}) int {
 	return compareString(a, b)
 }
 
+// Boolean is true or false. Strings accepted by strconv.ParseBool can be
+// converted to booleans, and numbers are true unless they are 0.
 var Boolean Type = booleanType{}
 
 type booleanType struct{}
@@ -142,6 +193,35 @@ func (t booleanType) Compare(a interface{}, b interface{}) int {
 	return compareBool(a, b)
 }
 
+// Null is the type of the NULL literal. NULL is represented by nil and can
+// be used wherever a value of any other type is expected.
+var Null Type = nullType{}
+
+type nullType struct{}
+
+func (t nullType) Name() string {
+	return "null"
+}
+
+func (t nullType) InternalType() reflect.Kind {
+	return reflect.Invalid
+}
+
+func (t nullType) Check(v interface{}) bool {
+	return v == nil
+}
+
+func (t nullType) Convert(v interface{}) (interface{}, error) {
+	if v != nil {
+		return nil, ErrInvalidType
+	}
+	return nil, nil
+}
+
+func (t nullType) Compare(a interface{}, b interface{}) int {
+	return 0
+}
+
 func checkString(v interface{}) bool {
 	_, ok := v.(string)
 	return ok
@@ -151,11 +231,23 @@ func convertToString(v interface{}) (interface{}, error) {
 	switch v.(type) {
 	case string:
 		return v.(string), nil
+	case time.Time:
+		return v.(time.Time).Format(TimestampLayouts[0]), nil
 	case fmt.Stringer:
 		return v.(fmt.Stringer).String(), nil
-	default:
-		return nil, ErrInvalidType
+	case bool:
+		return strconv.FormatBool(v.(bool)), nil
+	case float32:
+		return strconv.FormatFloat(float64(v.(float32)), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v.(float64), 'g', -1, 64), nil
+	}
+
+	i, err := convertToInt64(v)
+	if err != nil {
+		return nil, err
 	}
+	return strconv.FormatInt(i.(int64), 10), nil
 }
 
 func compareString(a interface{}, b interface{}) int {
@@ -207,13 +299,19 @@ func convertToInt32(v interface{}) (interface{}, error) {
 			return nil, fmt.Errorf("value %d overflows int32", v)
 		}
 		return int32(u), nil
+	case float32, float64:
+		f, err := truncateFloat(v, -(1 << 31), 1<<31)
+		if err != nil {
+			return nil, fmt.Errorf("value %v overflows int32", v)
+		}
+		return int32(f), nil
 	case string:
 		s := v.(string)
-		i, err := strconv.Atoi(s)
+		i, err := strconv.ParseInt(s, 10, 64)
 		if err != nil {
 			return nil, fmt.Errorf("value %q can't be converted to int32", v)
 		}
-		return int32(i), nil
+		return convertToInt32(i)
 	default:
 		return nil, ErrInvalidType
 	}
@@ -261,18 +359,39 @@ func convertToInt64(v interface{}) (interface{}, error) {
 			return nil, fmt.Errorf("value %d overflows int64", v)
 		}
 		return int64(u), nil
+	case float32, float64:
+		f, err := truncateFloat(v, -(1 << 63), 1<<63)
+		if err != nil {
+			return nil, fmt.Errorf("value %v overflows int64", v)
+		}
+		return int64(f), nil
 	case string:
 		s := v.(string)
-		i, err := strconv.Atoi(s)
+		i, err := strconv.ParseInt(s, 10, 64)
 		if err != nil {
 			return nil, fmt.Errorf("value %q can't be converted to int64", v)
 		}
-		return int64(i), nil
+		return i, nil
 	default:
 		return nil, ErrInvalidType
 	}
 }
 
+// truncateFloat returns the given float truncated towards zero, or an error
+// if the result is not in the range [min, max).
+func truncateFloat(v interface{}, min, max float64) (float64, error) {
+	f, err := convertToFloat64(v)
+	if err != nil {
+		return 0, err
+	}
+
+	t := math.Trunc(f.(float64))
+	if !(t >= min && t < max) {
+		return 0, fmt.Errorf("value %v is out of range", v)
+	}
+	return t, nil
+}
+
 func compareInt64(a interface{}, b interface{}) int {
 	av := a.(int64)
 	bv := b.(int64)
@@ -284,6 +403,68 @@ func compareInt64(a interface{}, b interface{}) int {
 	return 0
 }
 
+func checkFloat64(v interface{}) bool {
+	_, ok := v.(float64)
+	return ok
+}
+
+func convertToFloat64(v interface{}) (interface{}, error) {
+	switch v.(type) {
+	case float32:
+		return float64(v.(float32)), nil
+	case float64:
+		return v.(float64), nil
+	case string:
+		f, err := strconv.ParseFloat(v.(string), 64)
+		if err != nil {
+			return nil, fmt.Errorf("value %q can't be converted to float64", v)
+		}
+		return f, nil
+	}
+
+	i, err := convertToInt64(v)
+	if err != nil {
+		return nil, err
+	}
+	return float64(i.(int64)), nil
+}
+
+func compareFloat64(a interface{}, b interface{}) int {
+	av := a.(float64)
+	bv := b.(float64)
+	if av < bv {
+		return -1
+	} else if av > bv {
+		return 1
+	}
+	return 0
+}
+
+// TimestampLayouts are the layouts of the strings that can be converted to
+// timestamps. Times without a zone are in UTC.
+var TimestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+func convertToTimestamp(v interface{}) (interface{}, error) {
+	switch v.(type) {
+	case time.Time:
+		return v.(time.Time).Unix(), nil
+	case string:
+		for _, layout := range TimestampLayouts {
+			if t, err := time.Parse(layout, v.(string)); err == nil {
+				return t.Unix(), nil
+			}
+		}
+		return nil, fmt.Errorf("value %q can't be converted to timestamp", v)
+	default:
+		return convertToInt64(v)
+	}
+}
+
 func checkBoolean(v interface{}) bool {
 	_, ok := v.(bool)
 	return ok
@@ -293,9 +474,25 @@ func convertToBool(v interface{}) (interface{}, error) {
 	switch v.(type) {
 	case bool:
 		return v.(bool), nil
-	default:
-		return nil, ErrInvalidType
+	case string:
+		b, err := strconv.ParseBool(v.(string))
+		if err != nil {
+			return nil, fmt.Errorf("value %q can't be converted to bool", v)
+		}
+		return b, nil
+	case float32, float64:
+		f, err := convertToFloat64(v)
+		if err != nil {
+			return nil, err
+		}
+		return f.(float64) != 0, nil
+	}
+
+	i, err := convertToInt64(v)
+	if err != nil {
+		return nil, err
 	}
+	return i.(int64) != 0, nil
 }
 
 func compareBool(a interface{}, b interface{}) int {
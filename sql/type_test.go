@@ -2,7 +2,10 @@ package sql
 
 
 import (
+	"math"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -18,6 +21,21 @@ func TestType_String(t *testing.T) {
 	assert.Nil(err)
 	assert.Equal("", v)
 	v, err = String.Convert(1)
+	assert.Nil(err)
+	assert.Equal("1", v)
+	v, err = String.Convert(int64(-42))
+	assert.Nil(err)
+	assert.Equal("-42", v)
+	v, err = String.Convert(1.5)
+	assert.Nil(err)
+	assert.Equal("1.5", v)
+	v, err = String.Convert(true)
+	assert.Nil(err)
+	assert.Equal("true", v)
+	v, err = String.Convert(time.Date(2017, time.March, 16, 10, 30, 0, 0, time.UTC))
+	assert.Nil(err)
+	assert.Equal("2017-03-16T10:30:00Z", v)
+	v, err = String.Convert([]byte{})
 	assert.Equal(ErrInvalidType, err)
 	assert.Nil(v)
 }
@@ -51,6 +69,15 @@ func TestType_Integer(t *testing.T) {
 	v, err = Integer.Convert(uint64(18446744073709551615))
 	assert.NotNil(err)
 	assert.Nil(v)
+	v, err = Integer.Convert(1.9)
+	assert.Nil(err)
+	assert.Equal(int32(1), v)
+	v, err = Integer.Convert(float32(-2.5))
+	assert.Nil(err)
+	assert.Equal(int32(-2), v)
+	v, err = Integer.Convert(float64(1 << 31))
+	assert.EqualError(err, "value 2.147483648e+09 overflows int32")
+	assert.Nil(v)
 }
 
 func TestType_BigInteger(t *testing.T) {
@@ -82,4 +109,119 @@ func TestType_BigInteger(t *testing.T) {
 	v, err = BigInteger.Convert("")
 	assert.NotNil(err)
 	assert.Nil(v)
+	v, err = BigInteger.Convert("-9223372036854775808")
+	assert.Nil(err)
+	assert.Equal(int64(-9223372036854775808), v)
+	v, err = BigInteger.Convert(-1.9)
+	assert.Nil(err)
+	assert.Equal(int64(-1), v)
+	v, err = BigInteger.Convert(1e19)
+	assert.EqualError(err, "value 1e+19 overflows int64")
+	assert.Nil(v)
+	v, err = BigInteger.Convert(math.NaN())
+	assert.NotNil(err)
+	assert.Nil(v)
+}
+
+func TestType_Float(t *testing.T) {
+	var v interface{}
+	var err error
+	assert := assert.New(t)
+	assert.True(Float.Check(float64(1)))
+	assert.False(Float.Check(float32(1)))
+	assert.False(Float.Check(int64(1)))
+	v, err = Float.Convert(float32(1.5))
+	assert.Nil(err)
+	assert.Equal(float64(1.5), v)
+	v, err = Float.Convert(int32(3))
+	assert.Nil(err)
+	assert.Equal(float64(3), v)
+	v, err = Float.Convert(uint64(42))
+	assert.Nil(err)
+	assert.Equal(float64(42), v)
+	v, err = Float.Convert("-2.25")
+	assert.Nil(err)
+	assert.Equal(float64(-2.25), v)
+	v, err = Float.Convert("foo")
+	assert.NotNil(err)
+	assert.Nil(v)
+	v, err = Float.Convert(true)
+	assert.Equal(ErrInvalidType, err)
+	assert.Nil(v)
+	assert.Equal(-1, Float.Compare(float64(1), float64(1.5)))
+	assert.Equal(0, Float.Compare(float64(2), float64(2)))
+}
+
+func TestType_Timestamp(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(Timestamp.Check(int64(1)))
+	assert.False(Timestamp.Check("2017-01-01"))
+
+	testCases := map[interface{}]int64{
+		int64(1489674307):              1489674307,
+		"2017-03-16":                   1489622400,
+		"2017-03-16 14:25:07":          1489674307,
+		"2017-03-16T14:25:07":          1489674307,
+		"2017-03-16T16:25:07+02:00":    1489674307,
+		time.Unix(1489674307, 0).UTC(): 1489674307,
+	}
+
+	for v, expected := range testCases {
+		ts, err := Timestamp.Convert(v)
+		assert.Nil(err)
+		assert.Equal(expected, ts, "%v", v)
+	}
+
+	_, err := Timestamp.Convert("16/03/2017")
+	assert.EqualError(err, `value "16/03/2017" can't be converted to timestamp`)
+}
+
+func TestType_Boolean(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(Boolean.Check(true))
+	assert.False(Boolean.Check(1))
+	for v, expected := range map[interface{}]bool{
+		true:         true,
+		"true":       true,
+		"FALSE":      false,
+		"1":          true,
+		int32(0):     false,
+		int64(-3):    true,
+		float64(0.5): true,
+		float64(0):   false,
+	} {
+		b, err := Boolean.Convert(v)
+		assert.Nil(err)
+		assert.Equal(expected, b, "%v", v)
+	}
+	_, err := Boolean.Convert("yes")
+	assert.EqualError(err, `value "yes" can't be converted to bool`)
+	_, err = Boolean.Convert([]byte{})
+	assert.Equal(ErrInvalidType, err)
+}
+
+func TestType_Null(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(Null.Check(nil))
+	assert.False(Null.Check(int64(1)))
+	v, err := Null.Convert(nil)
+	assert.Nil(err)
+	assert.Nil(v)
+	_, err = Null.Convert("")
+	assert.NotNil(err)
+}
+
+func TestTypeByName(t *testing.T) {
+	assert := assert.New(t)
+	for _, typ := range []Type{Integer, BigInteger, Float, Timestamp, String, Boolean} {
+		found, err := TypeByName(strings.ToUpper(typ.Name()))
+		assert.Nil(err)
+		assert.Equal(typ, found)
+	}
+
+	_, err := TypeByName("null")
+	assert.EqualError(err, `unknown type "null"`)
+
+	_, err = Integer.Convert("3000000000")
+	assert.EqualError(err, "value 3000000000 overflows int32")
 }